@@ -0,0 +1,68 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/k8s"
+)
+
+const defaultDiagnosticsTimeout = 120
+
+// collectDiagnostics - store Kubernetes diagnostics of every cluster of failed task into its artifacts directory.
+func (ctx *executionContext) collectDiagnostics(task *testTask, clusterConfigs []string, writer *bufio.Writer) {
+	cfg := &task.test.ExecutionConfig.Diagnostics
+	if !cfg.Enabled || len(task.test.ArtifactDirectories) == 0 {
+		return
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultDiagnosticsTimeout
+	}
+	dir := task.test.ArtifactDirectories[len(task.test.ArtifactDirectories)-1]
+	for i, clusterConfig := range clusterConfigs {
+		target := filepath.Join(dir, "diagnostics", task.clusterInstances[i].id)
+		msg := fmt.Sprintf("%s: collecting Kubernetes diagnostics of cloud %v into %v", task.test.Name, task.clusterInstances[i].id, target)
+		logrus.Infof(msg)
+		_, _ = writer.WriteString(msg + "\n")
+
+		k8sUtils, err := k8s.NewK8sUtils(clusterConfig)
+		if err == nil {
+			timeoutCtx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+			err = k8sUtils.CollectDiagnostics(timeoutCtx, target, &k8s.DiagnosticsOptions{
+				Namespaces:    cfg.Namespaces,
+				LabelSelector: cfg.LabelSelector,
+				MaxLogSize:    cfg.MaxLogSize,
+				MaxTotalSize:  cfg.MaxTotalSize,
+			})
+			cancel()
+		}
+		if err != nil {
+			msg = fmt.Sprintf("%s: failed to collect Kubernetes diagnostics of cloud %v: %v", task.test.Name, task.clusterInstances[i].id, err)
+			logrus.Warnf(msg)
+			_, _ = writer.WriteString(msg + "\n")
+		}
+	}
+	_ = writer.Flush()
+}
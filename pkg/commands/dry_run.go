@@ -0,0 +1,193 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+)
+
+const (
+	planFormatTable = "table"
+	planFormatJSON  = "json"
+
+	planStatusPlanned = "planned"
+	planStatusSkipped = "skipped"
+)
+
+// planCluster - a cluster group of execution plan with computed number of instances.
+type planCluster struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Enabled    bool   `json:"enabled"`
+	Instances  int    `json:"instances"`
+	WarmSpares int    `json:"warm-spares,omitempty"`
+	Tasks      int    `json:"tasks"`
+}
+
+// planTask - a task of execution plan.
+type planTask struct {
+	Execution string   `json:"execution"`
+	Test      string   `json:"test"`
+	Kind      string   `json:"kind"`
+	Methods   []string `json:"methods,omitempty"`
+	Clusters  []string `json:"clusters"`
+	Status    string   `json:"status"`
+	Reason    string   `json:"reason,omitempty"`
+}
+
+// executionPlan - a tasks cloudtest would execute with given configuration and arguments.
+type executionPlan struct {
+	Clusters []*planCluster `json:"clusters"`
+	Tasks    []*planTask    `json:"tasks"`
+}
+
+// performDryRun - find tests, create cluster groups and tasks without starting any cluster and write execution plan.
+func performDryRun(ctx *executionContext, w io.Writer) error {
+	plan, err := ctx.buildPlan()
+	if err != nil {
+		return err
+	}
+	return writePlan(w, plan, ctx.arguments.dryRunFormat)
+}
+
+func (ctx *executionContext) buildPlan() (*executionPlan, error) {
+	switch ctx.arguments.dryRunFormat {
+	case "", planFormatTable, planFormatJSON:
+	default:
+		return nil, errors.Errorf("unknown plan format %v, supported formats: %v, %v", ctx.arguments.dryRunFormat, planFormatTable, planFormatJSON)
+	}
+	if err := ctx.findTests(); err != nil {
+		return nil, err
+	}
+	if err := ctx.shardTests(); err != nil {
+		return nil, err
+	}
+	if err := ctx.createClusters(); err != nil {
+		return nil, err
+	}
+	ctx.createTasks()
+	if err := ctx.scheduleTasks(); err != nil {
+		return nil, err
+	}
+
+	plan := &executionPlan{}
+	clusters := map[string]*planCluster{}
+	for _, cl := range ctx.cloudTestConfig.Providers {
+		pc := &planCluster{Name: cl.Name, Kind: cl.Kind}
+		clusters[cl.Name] = pc
+		plan.Clusters = append(plan.Clusters, pc)
+	}
+	for _, group := range ctx.clusters {
+		pc := clusters[group.config.Name]
+		pc.Enabled = true
+		for _, inst := range group.instances {
+			if inst.spare {
+				pc.WarmSpares++
+			} else {
+				pc.Instances++
+			}
+		}
+	}
+
+	addTasks := func(tasks []*testTask, status string) {
+		for _, task := range tasks {
+			pt := &planTask{
+				Execution: task.test.ExecutionConfig.Name,
+				Test:      task.test.Name,
+				Kind:      planTaskKind(task.test),
+				Status:    status,
+			}
+			if task.test.Suite != nil {
+				pt.Methods = task.test.Suite.Tests
+			}
+			if task.test.Status == model.StatusSkipped || task.test.Status == model.StatusSkippedSinceNoClusters {
+				pt.Status = planStatusSkipped
+				pt.Reason = task.test.SkipMessage
+			}
+			if pt.Status == planStatusSkipped && pt.Reason == "" {
+				pt.Reason = ctx.planSkipReason(task)
+			}
+			for _, group := range task.clusters {
+				pt.Clusters = append(pt.Clusters, group.config.Name)
+				if pt.Status == planStatusPlanned {
+					clusters[group.config.Name].Tasks++
+				}
+			}
+			plan.Tasks = append(plan.Tasks, pt)
+		}
+	}
+	addTasks(ctx.tasks, planStatusPlanned)
+	addTasks(ctx.skipped, planStatusSkipped)
+	addTasks(ctx.unsatisfied, planStatusSkipped)
+	return plan, nil
+}
+
+func (ctx *executionContext) planSkipReason(task *testTask) string {
+	if len(task.clusters) < task.test.ExecutionConfig.ClusterCount {
+		return fmt.Sprintf("Not all clusters defined of required %v", task.test.ExecutionConfig.ClusterSelector)
+	}
+	if ctx.arguments.count > 0 {
+		return fmt.Sprintf("By limit of number of tests to run: %v", ctx.arguments.count)
+	}
+	return ""
+}
+
+func planTaskKind(test *model.TestEntry) string {
+	switch test.Kind {
+	case model.ShellTestKind:
+		return "shell"
+	case model.SuiteTestKind:
+		return "suite"
+	case model.GinkgoTestKind:
+		return "ginkgo"
+	case model.SubtestsTestKind:
+		return "subtests"
+	}
+	return "gotest"
+}
+
+func writePlan(w io.Writer, plan *executionPlan, format string) error {
+	if format == planFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "CLUSTER\tKIND\tENABLED\tINSTANCES\tSPARES\tTASKS")
+	for _, c := range plan.Clusters {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%v\t%d\t%d\t%d\n", c.Name, c.Kind, c.Enabled, c.Instances, c.WarmSpares, c.Tasks)
+	}
+	_, _ = fmt.Fprintln(tw)
+	_, _ = fmt.Fprintln(tw, "EXECUTION\tTEST\tKIND\tCLUSTERS\tSTATUS\tDETAILS")
+	for _, t := range plan.Tasks {
+		details := t.Reason
+		if details == "" && len(t.Methods) > 0 {
+			details = strings.Join(t.Methods, ", ")
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", t.Execution, t.Test, t.Kind, strings.Join(t.Clusters, ","), t.Status, details)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/tests"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestDryRunPlan(t *testing.T) {
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	testConfig := createResumeConfig(tmpDir, "TestPass1", "TestPass2")
+	disabled := createProvider(testConfig, "b_provider", "echo starting")
+	disabled.Enabled = false
+
+	ctx := &executionContext{
+		cloudTestConfig: testConfig,
+		running:         map[string]*testTask{},
+		factory:         &tests.TestValidationFactory{},
+		arguments:       &Arguments{count: 1, dryRunFormat: planFormatJSON},
+		manager:         execmanager.NewExecutionManager(path.Join(tmpDir, "dry-run")),
+	}
+	plan, err := ctx.buildPlan()
+	require.NoError(t, err)
+
+	require.Len(t, plan.Clusters, 2)
+	require.Equal(t, "a_provider", plan.Clusters[0].Name)
+	require.True(t, plan.Clusters[0].Enabled)
+	require.Equal(t, 1, plan.Clusters[0].Instances)
+	require.Equal(t, 1, plan.Clusters[0].Tasks)
+	require.False(t, plan.Clusters[1].Enabled)
+	require.Equal(t, 0, plan.Clusters[1].Instances)
+
+	require.Len(t, plan.Tasks, 2)
+	require.ElementsMatch(t, []string{"TestPass1", "TestPass2"}, []string{plan.Tasks[0].Test, plan.Tasks[1].Test})
+	require.Equal(t, planStatusPlanned, plan.Tasks[0].Status)
+	require.Equal(t, []string{"a_provider"}, plan.Tasks[0].Clusters)
+	require.Equal(t, planStatusSkipped, plan.Tasks[1].Status)
+	require.Contains(t, plan.Tasks[1].Reason, "limit of number of tests")
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, writePlan(buffer, plan, planFormatJSON))
+	result := &executionPlan{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), result))
+	require.Equal(t, plan, result)
+
+	buffer.Reset()
+	require.NoError(t, writePlan(buffer, plan, planFormatTable))
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 7)
+	require.Regexp(t, `^CLUSTER\s+KIND\s+ENABLED\s+INSTANCES\s+SPARES\s+TASKS$`, lines[0])
+	require.Regexp(t, `^a_provider\s+shell\s+true\s+1\s+0\s+1$`, lines[1])
+	require.Regexp(t, `^b_provider\s+shell\s+false\s+0\s+0\s+0$`, lines[2])
+	require.Regexp(t, `^EXECUTION\s+TEST\s+KIND\s+CLUSTERS\s+STATUS\s+DETAILS$`, lines[4])
+	require.Regexp(t, `^simple\s+`+plan.Tasks[0].Test+`\s+gotest\s+a_provider\s+planned\s*$`, lines[5])
+	require.Regexp(t, `^simple\s+`+plan.Tasks[1].Test+`\s+gotest\s+a_provider\s+skipped\s+By limit of number of tests to run: 1$`, lines[6])
+}
+
+func TestDryRunKeepsResults(t *testing.T) {
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	marker := path.Join(tmpDir, "marker.txt")
+	require.NoError(t, ioutil.WriteFile(marker, []byte("previous run"), os.ModePerm))
+
+	report, err := PerformTesting(createResumeConfig(tmpDir, "TestPass1"), &tests.TestValidationFactory{}, &Arguments{dryRun: true})
+	require.NoError(t, err)
+	require.Nil(t, report)
+	require.FileExists(t, marker)
+
+	_, err = PerformTesting(createResumeConfig(tmpDir, "TestPass1"), &tests.TestValidationFactory{}, &Arguments{dryRun: true, dryRunFormat: "xml"})
+	require.Error(t, err)
+}
@@ -18,8 +18,8 @@ package commands
 
 import (
 	"bufio"
+	"bytes"
 	"context"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -38,10 +38,14 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/events"
 	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/ginkgo"
 	"github.com/networkservicemesh/cloudtest/pkg/k8s"
 	"github.com/networkservicemesh/cloudtest/pkg/model"
 	"github.com/networkservicemesh/cloudtest/pkg/providers"
+	"github.com/networkservicemesh/cloudtest/pkg/providers/existing"
+	"github.com/networkservicemesh/cloudtest/pkg/providers/kind"
 	"github.com/networkservicemesh/cloudtest/pkg/providers/packet"
 	"github.com/networkservicemesh/cloudtest/pkg/providers/shell"
 	"github.com/networkservicemesh/cloudtest/pkg/reporting"
@@ -64,6 +68,14 @@ type Arguments struct {
 	count           int      // Limit number of tests to be run per every cloud
 	instanceOptions providers.InstanceOptions
 	onlyRun         []string // A list of tests to run.
+	resume          bool     // Resume previous interrupted run using stored run state.
+	shardIndex      int      // An index of shard to execute.
+	shardTotal      int      // A total number of shards, tests are split between shards.
+	shardReport     string   // A previous JUnit report to balance shards by test durations.
+	dryRun          bool     // Print planned tasks without starting clusters.
+	dryRunFormat    string   // A format of planned tasks, 'table' or 'json'.
+
+	metrics *schedulerMetrics // A metrics endpoint, nil if disabled.
 }
 
 type clusterState uint32
@@ -111,11 +123,13 @@ type clusterInstance struct {
 	taskCancel       context.CancelFunc
 	cancelMonitor    context.CancelFunc
 	startTime        time.Time
+	spare            bool // A warm spare instance, started in background and not used to run tasks until it replaces failed one.
 
 	currentTask string
 
 	executions    []*clusterOperationRecord
-	retestCounter int // If test is requesting retest on this cluster instance, we count how many times it is happening, it will be set to 0 if test is not request retest.
+	retestCounter int  // If test is requesting retest on this cluster instance, we count how many times it is happening, it will be set to 0 if test is not request retest.
+	destroying    bool // Cluster instance is destroyed in background, it could not be started again until destroy is finished.
 }
 
 func (ci *clusterInstance) isDownOr(states ...clusterState) bool {
@@ -135,12 +149,17 @@ type clustersGroup struct {
 	config    *config.ClusterProviderConfig
 	tasks     map[string]*testTask // All tasks assigned to this cluster.
 	completed map[string]*testTask
+
+	startAttempts int // A number of instance start attempts.
+	crashes       int // A number of instance crashes, including failed starts.
+	notAvailable  int // A number of times instance is marked as not available.
 }
 
 type testTask struct {
 	taskID           string
 	test             *model.TestEntry
 	clusters         []*clustersGroup
+	candidates       []*clustersGroup // Cluster groups matching execution requirements, task is executed on any of them.
 	clusterInstances []*clusterInstance
 	clusterTaskID    string
 }
@@ -169,6 +188,7 @@ type executionContext struct {
 	running            map[string]*testTask
 	completed          []*testTask
 	skipped            []*testTask
+	unsatisfied        []*testTask
 	failedTestsCount   int
 	cloudTestConfig    *config.CloudTestConfig
 	report             *reporting.JUnitFile
@@ -177,6 +197,11 @@ type executionContext struct {
 	factory            k8s.ValidationFactory
 	arguments          *Arguments
 	clusterWaitGroup   sync.WaitGroup // Wait group for clusters destroying
+	eventStream        *events.Stream // A live events stream, nil if disabled
+	retestCount        int            // A number of test re-runs
+	healthFailures     int            // A number of failed health check probes
+
+	binaries map[string]string // Precompiled test binaries by package root and tags.
 }
 
 // CloudTestRun - CloudTestRun
@@ -209,7 +234,16 @@ func CloudTestRun(cmd *cloudTestCmd) {
 		os.Exit(1)
 	}
 
+	if testConfig.Metrics.Listen != "" {
+		cmd.cmdArguments.metrics, err = startMetrics(testConfig.Metrics.Listen)
+		if err != nil {
+			logrus.Errorf("Failed to start metrics endpoint %v", err)
+			os.Exit(1)
+		}
+	}
+
 	_, err = PerformTesting(testConfig, k8s.CreateFactory(), cmd.cmdArguments)
+	cmd.cmdArguments.metrics.close()
 	if err != nil {
 		logrus.Errorf("Failed to process tests %v", err)
 		os.Exit(1)
@@ -217,25 +251,30 @@ func CloudTestRun(cmd *cloudTestCmd) {
 }
 
 func performImport(testConfig *config.CloudTestConfig) error {
-	for _, imp := range testConfig.Imports {
+	imports, err := resolveImports(testConfig.Imports)
+	if err != nil {
+		return err
+	}
+	return importFiles(testConfig, imports...)
+}
+
+// resolveImports - return a list of files to import, an import is a file name or a folder with file name pattern.
+func resolveImports(imports []string) ([]string, error) {
+	var result []string
+	for _, imp := range imports {
 		if utils.FileExists(imp) {
-			if err := importFiles(testConfig, imp); err != nil {
-				return err
-			}
+			result = append(result, imp)
 			continue
 		}
 		dir, pattern := filepath.Split(imp)
 		files := utils.GetAllFiles(dir)
-		imports, err := utils.FilterByPattern(files, pattern)
+		matched, err := utils.FilterByPattern(files, pattern)
 		if err != nil {
-			return err
-		}
-		if err := importFiles(testConfig, imports...); err != nil {
-			return err
+			return nil, err
 		}
+		result = append(result, matched...)
 	}
-
-	return nil
+	return result, nil
 }
 
 func importFiles(testConfig *config.CloudTestConfig, files ...string) error {
@@ -281,6 +320,21 @@ func PerformTesting(config *config.CloudTestConfig, factory k8s.ValidationFactor
 		}
 	}
 
+	manager := execmanager.NewExecutionManager
+	if arguments.resume {
+		manager = execmanager.NewResumedExecutionManager
+	}
+	root := config.ConfigRoot
+	if arguments.dryRun {
+		// Results of previous run should not be cleared by dry run.
+		tmpDir, err := ioutil.TempDir(os.TempDir(), "cloudtest-dry-run")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create temporary folder")
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		root = tmpDir
+	}
+
 	ctx := &executionContext{
 		cloudTestConfig:    config,
 		operationChannel:   make(chan operationEvent, 100),
@@ -291,17 +345,39 @@ func PerformTesting(config *config.CloudTestConfig, factory k8s.ValidationFactor
 		tests:              []*model.TestEntry{},
 		factory:            factory,
 		arguments:          arguments,
-		manager:            execmanager.NewExecutionManager(config.ConfigRoot),
+		manager:            manager(root),
 	}
+	if arguments.dryRun {
+		return nil, performDryRun(ctx, os.Stdout)
+	}
+	arguments.metrics.attach(ctx)
 	return performTestingContext(ctx)
 }
 
 func performTestingContext(ctx *executionContext) (*reporting.JUnitFile, error) {
+	if err := ctx.checkReporters(); err != nil {
+		logrus.Errorf("Invalid reporting configuration: %v", err)
+		return nil, err
+	}
+	// Open live events stream
+	if err := ctx.openEventStream(); err != nil {
+		return nil, err
+	}
+	defer ctx.closeEventStream()
+
 	// Collect tests
 	if err := ctx.findTests(); err != nil {
 		logrus.Errorf("Error finding tests %v", err)
 		return nil, err
 	}
+	// Select tests of current shard
+	if err := ctx.shardTests(); err != nil {
+		return nil, err
+	}
+	if len(ctx.tests) == 0 && ctx.arguments != nil && ctx.arguments.shardTotal > 1 {
+		logrus.Warnf("There is no tests for current shard")
+		return ctx.generateJUnitReportFile()
+	}
 	// Create cluster instance handles
 	if err := ctx.createClusters(); err != nil {
 		return nil, err
@@ -313,8 +389,22 @@ func performTestingContext(ctx *executionContext) (*reporting.JUnitFile, error)
 	defer ctx.performShutdown()
 	// Fill tasks to be executed..
 	ctx.createTasks()
+	// Skip tasks completed by previous session.
+	if err := ctx.resumeState(); err != nil {
+		return nil, err
+	}
+	// Build test binaries once per package.
+	ctx.compileTests()
+	// Order tasks according to scheduling mode.
+	if err := ctx.scheduleTasks(); err != nil {
+		return nil, err
+	}
 
+	ctx.eventStream.Emit(&events.Event{Kind: events.RunStarted})
 	err := ctx.performExecution()
+	ctx.updateTimings()
+	ctx.updateHistory()
+	ctx.emitRunFinished(err)
 	result, err2 := ctx.generateJUnitReportFile()
 	if err2 != nil {
 		logrus.Errorf("Error during generation of report: %v", err2)
@@ -386,9 +476,20 @@ func (ctx *executionContext) performExecution() error {
 	statTicker := time.NewTicker(statsTimeout)
 	defer statTicker.Stop()
 
+	ctx.Lock()
+	noTasks := len(ctx.tasks) == 0 && len(ctx.running) == 0
+	ctx.Unlock()
+	if noTasks {
+		logrus.Info("No tasks to execute")
+		return nil
+	}
+
 	for {
+		ctx.maintainSpares()
 		// WE take 1 test task from list and do execution.
 		ctx.assignTasks()
+		// Spares are not required once last pending task is assigned.
+		ctx.maintainSpares()
 		ctx.checkClustersUsage()
 
 		if err := ctx.pollEvents(timeoutCtx, termChannel, statTicker.C); err != nil {
@@ -416,10 +517,20 @@ func (ctx *executionContext) pollEvents(c context.Context, osCh <-chan os.Signal
 			ctx.processTaskUpdate(event)
 		}
 	case <-osCh:
-		return errors.New("termination request is received")
+		err := errors.New("termination request is received")
+		ctx.emitTermination(err)
+		return err
 	case <-c.Done():
-		return errors.Errorf("global timeout elapsed: %v seconds", ctx.cloudTestConfig.Timeout)
+		err := errors.Errorf("global timeout elapsed: %v seconds", ctx.cloudTestConfig.Timeout)
+		ctx.emitTermination(err)
+		return err
 	case err := <-ctx.terminationChannel:
+		if _, ok := err.(*healthCheckError); ok {
+			ctx.Lock()
+			ctx.healthFailures++
+			ctx.Unlock()
+		}
+		ctx.emitTermination(err)
 		return err
 	case <-statsCh:
 		if ctx.cloudTestConfig.Statistics.Enabled {
@@ -456,6 +567,10 @@ func (ctx *executionContext) assignTasks() {
 			continue
 		}
 
+		if len(task.candidates) > 0 && len(assignedClusters) > 0 {
+			ctx.useCandidate(task, assignedClusters[0].group)
+		}
+
 		canRun := len(assignedClusters) == len(task.clusters)
 		if canRun {
 			// Start task execution.
@@ -490,12 +605,19 @@ func (ctx *executionContext) skipTaskDueUnavailableClusters(task *testTask, unav
 		cl.completed[task.test.Key] = task
 	}
 	ctx.completed = append(ctx.completed, task)
+	ctx.emitTaskEvent(events.TaskFinished, task, fmt.Sprintf("required cluster(s) unavailable: %v", unavailableClusterNames))
+	ctx.checkpointState()
 }
 
 func (ctx *executionContext) performClusterUpdate(event operationEvent) {
 	ctx.Lock()
 	defer ctx.Unlock()
 	logrus.Infof("Cluster instance %s is updated: state: %v", event.clusterInstance.id, fromClusterState(event.clusterInstance))
+	ctx.eventStream.Emit(&events.Event{
+		Kind:            events.ClusterState,
+		ClusterInstance: event.clusterInstance.id,
+		Status:          fromClusterState(event.clusterInstance),
+	})
 	if event.clusterInstance.taskCancel != nil && event.clusterInstance.state.load() == clusterCrashed {
 		// We have task running on cluster
 		event.clusterInstance.taskCancel()
@@ -528,8 +650,14 @@ func (ctx *executionContext) processTaskUpdate(event operationEvent) {
 				cl.completed[event.task.test.Key] = event.task
 			}
 		}
+		ctx.emitTaskEvent(events.TaskFinished, event.task, "")
 		ctx.completeTask(event)
+		ctx.checkpointState()
 	} else {
+		ctx.Lock()
+		ctx.retestCount++
+		ctx.Unlock()
+		ctx.emitTaskEvent(events.TaskRetest, event.task, "")
 		if event.task.test.Status == model.StatusRerunRequest && ctx.cloudTestConfig.RetestConfig.WarmupTimeout > 0 {
 			go func() {
 				var ids []string
@@ -554,7 +682,7 @@ func (ctx *executionContext) completeTask(event operationEvent) {
 	ctx.Lock()
 	delete(ctx.running, event.task.taskID)
 	ctx.completed = append(ctx.completed, event.task)
-	if event.task.test.Status == model.StatusFailed {
+	if event.task.test.Status == model.StatusFailed && !ctx.isQuarantined(event.task.test) {
 		ctx.failedTestsCount++
 	}
 	if ctx.cloudTestConfig.FailedTestsLimit != 0 && ctx.failedTestsCount == ctx.cloudTestConfig.FailedTestsLimit {
@@ -568,6 +696,13 @@ func (ctx *executionContext) rescheduleTask(event operationEvent) {
 	ctx.makeInstancesReady(event.task.clusterInstances)
 	ctx.Lock()
 	delete(ctx.running, event.task.taskID)
+	if len(event.task.candidates) > 0 {
+		// Task could be executed on any of matching cluster groups again.
+		event.task.clusters = event.task.candidates
+		for _, cl := range event.task.candidates {
+			cl.tasks[event.task.test.Key] = event.task
+		}
+	}
 	ctx.tasks = append(ctx.tasks, event.task)
 	ctx.Unlock()
 	ctx.sendClustersUpdate(event.task.clusterInstances)
@@ -606,43 +741,166 @@ func statusName(status model.Status) interface{} {
 		return "timeout"
 	case model.StatusRerunRequest:
 		return "rerun-request"
+	case model.StatusRetryRequest:
+		return "retry-request"
+	case model.StatusSkippedSinceNoClusters:
+		return "skipped-no-clusters"
 	}
 	return fmt.Sprintf("code: %v", status)
 }
 
 func (ctx *executionContext) selectClustersForTask(task *testTask) (clustersToUse []*clusterInstance, unavailableClusters []*clustersGroup) {
+	if len(task.candidates) > 0 {
+		return ctx.selectCandidateForTask(task)
+	}
 	for _, cluster := range task.clusters {
-		groupAssigned := false
-		groupAvailable := false
-		ctx.Lock()
-		for _, ci := range cluster.instances {
-			// No task is assigned for cluster.
-			switch ci.state.load() {
-			case clusterAdded, clusterCrashed:
-				// Try starting cluster
-				if ctx.startCluster(ci) {
-					groupAvailable = true
-				}
-			case clusterReady:
+		ci, failedInstance, groupAvailable, otherAvailable := ctx.selectInstance(task, cluster)
+		if ci == nil && failedInstance != nil && !otherAvailable {
+			ci = failedInstance
+		}
+		if ci != nil {
+			clustersToUse = append(clustersToUse, ci)
+		}
+		if !groupAvailable {
+			unavailableClusters = append(unavailableClusters, cluster)
+		}
+	}
+	return
+}
+
+// selectCandidateForTask - select instance of any cluster group matching task requirements,
+// task is skipped only if all of matching groups are unavailable.
+func (ctx *executionContext) selectCandidateForTask(task *testTask) (clustersToUse []*clusterInstance, unavailableClusters []*clustersGroup) {
+	var failedInstance *clusterInstance
+	otherAvailable := false
+	for _, cluster := range task.candidates {
+		ci, failed, groupAvailable, other := ctx.selectInstance(task, cluster)
+		if ci != nil {
+			return []*clusterInstance{ci}, nil
+		}
+		if failedInstance == nil {
+			failedInstance = failed
+		}
+		otherAvailable = otherAvailable || other
+		if !groupAvailable {
+			unavailableClusters = append(unavailableClusters, cluster)
+		}
+	}
+	if failedInstance != nil && !otherAvailable {
+		return []*clusterInstance{failedInstance}, nil
+	}
+	if len(unavailableClusters) < len(task.candidates) {
+		return nil, nil
+	}
+	return nil, unavailableClusters
+}
+
+// selectInstance - select ready instance of cluster group to execute task on, instance task is failed on is returned separately.
+func (ctx *executionContext) selectInstance(task *testTask, cluster *clustersGroup) (instance, failedInstance *clusterInstance, groupAvailable, otherAvailable bool) {
+	// A failed instance is used for retry only if there is no other instance could execute the task.
+	ctx.Lock()
+	defer ctx.Unlock()
+	for _, ci := range cluster.instances {
+		if ci.spare {
+			// Spare will replace failed instance as soon as it is ready.
+			if !ci.isDownOr() {
 				groupAvailable = true
-				// Check if we match requirements.
-				// We could assign task and start it running.
-				clustersToUse = append(clustersToUse, ci)
-				// We need to remove task from list
-				groupAssigned = true
-			case clusterBusy, clusterStarting, clusterStopping:
+				otherAvailable = true
+			}
+			continue
+		}
+		failed := isRetryOn(task, ci)
+		// No task is assigned for cluster.
+		switch ci.state.load() {
+		case clusterAdded, clusterCrashed:
+			// Try starting cluster
+			if ctx.startCluster(ci) {
 				groupAvailable = true
+				otherAvailable = otherAvailable || !failed
 			}
-			if groupAssigned {
-				break
+		case clusterReady:
+			groupAvailable = true
+			if failed {
+				failedInstance = ci
+				continue
 			}
+			// Check if we match requirements.
+			// We could assign task and start it running.
+			return ci, failedInstance, groupAvailable, otherAvailable
+		case clusterBusy, clusterStarting, clusterStopping:
+			groupAvailable = true
+			otherAvailable = otherAvailable || !failed
 		}
-		ctx.Unlock()
-		if !groupAvailable {
-			unavailableClusters = append(unavailableClusters, cluster)
+	}
+	return nil, failedInstance, groupAvailable, otherAvailable
+}
+
+// maintainSpares - keep warm spare instances started while group has pending tasks,
+// replace failed instances with ready spares and shutdown spares once there is no pending tasks.
+// Spares are started again if tasks are returned to queue by retry or rerun.
+func (ctx *executionContext) maintainSpares() {
+	var toShutdown []*clusterInstance
+
+	ctx.Lock()
+	pending := map[*clustersGroup]int{}
+	for _, task := range ctx.tasks {
+		for _, group := range task.clusters {
+			pending[group]++
 		}
 	}
-	return
+	for _, group := range ctx.clusters {
+		if group.config.WarmSpares == 0 {
+			continue
+		}
+		if pending[group] == 0 {
+			for _, inst := range group.instances {
+				if !inst.spare {
+					continue
+				}
+				switch inst.state.load() {
+				case clusterReady:
+					// Shutdown of ready spare is not a failure, so restarts are counted from scratch.
+					inst.startCount = 0
+					toShutdown = append(toShutdown, inst)
+				case clusterAdded, clusterCrashed:
+					inst.state.store(clusterShutdown)
+				}
+			}
+			continue
+		}
+		for _, inst := range group.instances {
+			if inst.spare && inst.state.load() == clusterShutdown && !inst.destroying {
+				// Tasks are returned to queue by retry or rerun, so spare is required again.
+				logrus.Infof("Pending tasks for cluster group %v. Restart of spare instance %v", group.config.Name, inst.id)
+				inst.state.store(clusterAdded)
+			}
+		}
+		for _, inst := range group.instances {
+			if inst.spare || !inst.isDownOr() || inst.state.load() == clusterShutdown {
+				continue
+			}
+			for _, spare := range group.instances {
+				if spare.spare && spare.state.load() == clusterReady {
+					logrus.Infof("Spare cluster instance %s replaces %s (%v)", spare.id, inst.id, fromClusterState(inst))
+					spare.spare = false
+					inst.spare = true
+					break
+				}
+			}
+		}
+		for _, inst := range group.instances {
+			if inst.spare {
+				ctx.startCluster(inst)
+			}
+		}
+	}
+	ctx.Unlock()
+
+	for _, inst := range toShutdown {
+		logrus.Infof("No pending tasks for cluster group %v. Shutdown of spare instance %v", inst.group.config.Name, inst.id)
+		_ = ctx.destroyCluster(inst, false, true)
+		inst.state.store(clusterShutdown)
+	}
 }
 
 func (ctx *executionContext) printStatistics() {
@@ -667,7 +925,11 @@ func (ctx *executionContext) printStatistics() {
 		_, _ = clustersMsg.WriteString(fmt.Sprintf("\t\tCluster: %v Tasks left: %v\n", cl.config.Name, len(cl.tasks)))
 		ctx.RLock()
 		for _, inst := range cl.instances {
-			_, _ = clustersMsg.WriteString(fmt.Sprintf("\t\t\t%s: %v, uptime: %v\n", inst.id, fromClusterState(inst),
+			spare := ""
+			if inst.spare {
+				spare = " (spare)"
+			}
+			_, _ = clustersMsg.WriteString(fmt.Sprintf("\t\t\t%s%s: %v, uptime: %v\n", inst.id, spare, fromClusterState(inst),
 				time.Since(inst.startTime).Round(time.Second)))
 		}
 		ctx.RUnlock()
@@ -751,20 +1013,22 @@ func (ctx *executionContext) createTasks() {
 	}
 }
 
-func (ctx *executionContext) splitTest(test *model.TestEntry, cluster *clustersGroup) []*model.TestEntry {
+func (ctx *executionContext) splitTest(test *model.TestEntry, instanceCount int) []*model.TestEntry {
 	if test.Suite == nil {
 		return []*model.TestEntry{test}
 	}
 	var result []*model.TestEntry
-	countPerInstance := len(test.Suite.Tests) / len(cluster.instances)
+	countPerInstance := len(test.Suite.Tests) / instanceCount
 	if countPerInstance < ctx.cloudTestConfig.MinSuiteSize {
 		countPerInstance = ctx.cloudTestConfig.MinSuiteSize
 	}
-	for i := 0; i < len(cluster.instances); i++ {
+	for i := 0; i < instanceCount; i++ {
 		splitTest := &model.TestEntry{
 			Kind:            test.Kind,
 			Name:            test.Name,
 			Tags:            test.Tags,
+			Package:         test.Package,
+			SplitFrom:       test.Name,
 			Status:          test.Status,
 			ExecutionConfig: test.ExecutionConfig,
 			Executions:      []model.TestEntryExecution{},
@@ -774,7 +1038,7 @@ func (ctx *executionContext) splitTest(test *model.TestEntry, cluster *clustersG
 		splitTest.Suite = &model.Suite{
 			Name: test.Suite.Name,
 		}
-		if len(test.Suite.Tests)-(i+1)*countPerInstance < countPerInstance || i+1 == len(cluster.instances) {
+		if len(test.Suite.Tests)-(i+1)*countPerInstance < countPerInstance || i+1 == instanceCount {
 			splitTest.Suite.Tests = test.Suite.Tests[i*countPerInstance:]
 			result = append(result, splitTest)
 			return result
@@ -787,7 +1051,12 @@ func (ctx *executionContext) splitTest(test *model.TestEntry, cluster *clustersG
 
 func (ctx *executionContext) createTask(entry *model.TestEntry, taskIndex, taskOrderIndex int) int {
 	selector := entry.ExecutionConfig.ClusterSelector
-	// In case of one cluster, we create task copies and execute on every cloud.
+	if reason := ctx.unsatisfiedRequirements(entry.ExecutionConfig); reason != "" {
+		ctx.skipUnsatisfiedTest(taskIndex, entry, reason)
+		return taskIndex + 1
+	}
+	// In case of one cluster, we create task copies and execute on every cloud matching selector,
+	// test with cluster requirements is executed once on any of matching clouds.
 	updateTaskStatus := func(task *testTask) {
 		if task == nil {
 			logrus.Errorf("%v: No clusters defined of required %+v", entry.Name, selector)
@@ -806,7 +1075,7 @@ func (ctx *executionContext) createTask(entry *model.TestEntry, taskIndex, taskO
 			for _, cluster := range ctx.clusters {
 				if clusterName == cluster.config.Name {
 					if len(tasks) == 0 {
-						for _, test := range ctx.splitTest(entry, cluster) {
+						for _, test := range ctx.splitTest(entry, len(cluster.instances)) {
 							task := ctx.createSingleTask(taskIndex, test, cluster, taskOrderIndex)
 							defer updateTaskStatus(task)
 							tasks = append(tasks, task)
@@ -822,11 +1091,29 @@ func (ctx *executionContext) createTask(entry *model.TestEntry, taskIndex, taskO
 				}
 			}
 		}
+	} else if len(entry.ExecutionConfig.ClusterRequirements) > 0 {
+		var candidates []*clustersGroup
+		instanceCount := 0
+		for _, cluster := range ctx.clusters {
+			if matchesExecution(entry.ExecutionConfig, cluster.config) {
+				candidates = append(candidates, cluster)
+				instanceCount += len(cluster.instances)
+			}
+		}
+		for _, test := range ctx.splitTest(entry, instanceCount) {
+			task := ctx.createSingleTask(taskIndex, test, candidates[0], taskOrderIndex)
+			task.candidates = candidates
+			for _, cluster := range candidates[1:] {
+				task.clusters = append(task.clusters, cluster)
+				cluster.tasks[task.test.Key] = task
+			}
+			task.clusterTaskID = makeTaskClusterID(task.clusters)
+			taskIndex++
+		}
 	} else {
 		for _, cluster := range ctx.clusters {
-			if len(selector) > 0 && utils.Contains(selector, cluster.config.Name) ||
-				len(selector) == 0 {
-				for _, test := range ctx.splitTest(entry, cluster) {
+			if matchesExecution(entry.ExecutionConfig, cluster.config) {
+				for _, test := range ctx.splitTest(entry, len(cluster.instances)) {
 					task := ctx.createSingleTask(taskIndex, test, cluster, taskOrderIndex)
 					updateTaskStatus(task)
 					taskIndex++
@@ -838,6 +1125,18 @@ func (ctx *executionContext) createTask(entry *model.TestEntry, taskIndex, taskO
 	return taskIndex
 }
 
+// useCandidate - execute task on selected cluster group, task is removed from other matching groups.
+func (ctx *executionContext) useCandidate(task *testTask, group *clustersGroup) {
+	ctx.Lock()
+	defer ctx.Unlock()
+	for _, cl := range task.candidates {
+		if cl != group {
+			delete(cl.tasks, task.test.Key)
+		}
+	}
+	task.clusters = []*clustersGroup{group}
+}
+
 func (ctx *executionContext) createSingleTask(taskIndex int, test *model.TestEntry, cluster *clustersGroup, taskOrderIndex int) *testTask {
 	task := &testTask{
 		taskID: fmt.Sprintf("%d", taskIndex),
@@ -845,6 +1144,8 @@ func (ctx *executionContext) createSingleTask(taskIndex int, test *model.TestEnt
 			Kind:            test.Kind,
 			Name:            test.Name,
 			Tags:            test.Tags,
+			Package:         test.Package,
+			SplitFrom:       test.SplitFrom,
 			Status:          test.Status,
 			Suite:           test.Suite,
 			ExecutionConfig: test.ExecutionConfig,
@@ -863,6 +1164,10 @@ func (ctx *executionContext) createSingleTask(taskIndex int, test *model.TestEnt
 		testKey += clusterName
 	}
 	task.test.Key = fmt.Sprintf("%s_%s", testKey, test.Name)
+	if test.Package != "" {
+		// Tests of different packages could have same names.
+		task.test.Key = fmt.Sprintf("%s_%s_%s", testKey, test.Package, test.Name)
+	}
 
 	// To track cluster task executions.
 	cluster.tasks[task.test.Key] = task
@@ -928,12 +1233,17 @@ func (ctx *executionContext) startTask(task *testTask, instances []*clusterInsta
 	case model.ShellTestKind:
 		runner = runners.NewShellTestRunner(task.clusterTaskID, task.test)
 	case model.GoTestKind:
-		runner = runners.NewGoTestRunner(task.clusterTaskID, task.test, timeout)
-	case model.SuiteTestKind:
-		runner = runners.NewSuiteRunner(task.clusterTaskID, task.test, timeout)
+		runner = runners.NewGoTestRunner(task.clusterTaskID, task.test, timeout, ctx.testBinary(task.test))
+	case model.SuiteTestKind, model.SubtestsTestKind:
+		runner = runners.NewSuiteRunner(task.clusterTaskID, task.test, timeout, ctx.testBinary(task.test))
+	case model.GinkgoTestKind:
+		runner = runners.NewGinkgoRunner(task.clusterTaskID, task.test, timeout, ginkgo.ReportFile(fileName))
 	default:
 		return errors.New("invalid task runner")
 	}
+	if task.test.ExecutionConfig.Image != "" {
+		runner = runners.InContainer(runner, runners.NewContainer(task.clusterTaskID, task.test))
+	}
 
 	go ctx.executeTask(task, clusterConfigs, file, runner, timeout, instances, fileName)
 	return nil
@@ -1009,6 +1319,7 @@ func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string
 	ctx.handleBeforeAfterScripts(task, writer, clusterConfigs, instances)
 	task.test.Started = time.Now()
 	ctx.Unlock()
+	ctx.emitTaskEvent(events.TaskStarted, task, "")
 
 	errCode := runner.Run(timeoutCtx, env, writer)
 
@@ -1031,8 +1342,10 @@ func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string
 			if onFailErr != nil {
 				errCode = errors.Wrap(errCode, onFailErr.Error())
 			}
+			ctx.emitOnFail(task, task.clusterInstances[i], onFailErr)
 
 		}
+		ctx.collectDiagnostics(task, clusterConfigs, writer)
 	}
 
 	// Check if test ask us restart it, and have few executions left
@@ -1088,6 +1401,9 @@ func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string
 			if err != nil {
 				logrus.Errorf("Task failed because cluster is not valid: %v %v %v", task.test.Name, inst.id, err)
 				clusterNotAvailable = true
+				ctx.Lock()
+				inst.group.crashes++
+				ctx.Unlock()
 				_ = ctx.destroyCluster(inst, true, false)
 			}
 			ctx.Lock()
@@ -1101,6 +1417,11 @@ func (ctx *executionContext) executeTask(task *testTask, clusterConfigs []string
 			logrus.Errorf(errCode.Error())
 			_, _ = writer.WriteString(errCode.Error())
 			_ = writer.Flush()
+			if ctx.shouldRetry(task) {
+				logrus.Infof("Test %v failed on %v, will be retried", task.test.Name, task.clusterTaskID)
+				ctx.updateTestExecution(task, fileName, model.StatusRetryRequest)
+				return
+			}
 			ctx.updateTestExecution(task, fileName, model.StatusFailed)
 		}
 	} else {
@@ -1176,11 +1497,19 @@ func (ctx *executionContext) getTestTimeout(task *testTask) time.Duration {
 
 func (ctx *executionContext) updateTestExecution(task *testTask, fileName string, status model.Status) {
 	task.test.Status = status
-	task.test.Executions = append(task.test.Executions, model.TestEntryExecution{
+	execution := model.TestEntryExecution{
 		Status:     status,
 		Retry:      len(task.test.Executions) + 1,
 		OutputFile: fileName,
-	})
+		Started:    task.test.Started,
+	}
+	for _, ci := range task.clusterInstances {
+		execution.ClusterInstances = append(execution.ClusterInstances, ci.id)
+	}
+	if !task.test.Started.IsZero() {
+		execution.Duration = time.Since(task.test.Started)
+	}
+	task.test.Executions = append(task.test.Executions, execution)
 	ctx.operationChannel <- operationEvent{
 		task: task,
 		kind: eventTaskUpdate,
@@ -1197,6 +1526,7 @@ func (ctx *executionContext) startCluster(ci *clusterInstance) bool {
 	if ci.startCount > ci.group.config.RetryCount {
 		logrus.Infof("Marking cluster %v as not available, (re)starts: %v", ci.id, ci.group.config.RetryCount)
 		ci.state.store(clusterNotAvailable)
+		ci.group.notAvailable++
 		return false
 	}
 
@@ -1209,14 +1539,19 @@ func (ctx *executionContext) startCluster(ci *clusterInstance) bool {
 		timeout := ctx.getClusterTimeout(ci.group)
 		ctx.Lock()
 		ci.startCount++
+		ci.group.startAttempts++
 		execution.attempt = ci.startCount
 		ctx.Unlock()
 		errFile, err := ci.instance.Start(timeout)
+		execution.duration = time.Since(execution.time)
 		if err != nil {
 			execution.logFile = errFile
 			execution.errMsg = err
 			execution.status.store(clusterCrashed)
 			ci.state.store(clusterStopping)
+			ctx.Lock()
+			ci.group.crashes++
+			ctx.Unlock()
 			destroyErr := ctx.destroyCluster(ci, true, false)
 			if destroyErr != nil {
 				logrus.Errorf("Both start and destroy of cluster returned errors, stop retrying operations with this cluster %v", ci.instance)
@@ -1226,7 +1561,6 @@ func (ctx *executionContext) startCluster(ci *clusterInstance) bool {
 		} else {
 			execution.status.store(clusterReady)
 		}
-		execution.duration = time.Since(execution.time)
 		// Starting cloud monitoring thread
 		if ci.state.load() != clusterCrashed {
 			monitorContext, monitorCancel := context.WithCancel(context.Background())
@@ -1257,6 +1591,9 @@ func (ctx *executionContext) monitorCluster(context context.Context, ci *cluster
 		err := ci.instance.CheckIsAlive()
 		if err != nil {
 			logrus.Errorf("Failed to interact with %s: %v", ci.id, err)
+			ctx.Lock()
+			ci.group.crashes++
+			ctx.Unlock()
 			_ = ctx.destroyCluster(ci, true, false)
 			break
 		}
@@ -1295,6 +1632,7 @@ func (ctx *executionContext) destroyCluster(ci *clusterInstance, sendUpdate, for
 	if ci.cancelMonitor != nil {
 		ci.cancelMonitor()
 	}
+	ci.destroying = fork
 	ctx.Unlock()
 
 	timeout := ctx.getClusterTimeout(ci.group)
@@ -1306,6 +1644,9 @@ func (ctx *executionContext) destroyCluster(ci *clusterInstance, sendUpdate, for
 			if err != nil {
 				logrus.Errorf("Failed to destroy cluster")
 			}
+			ctx.Lock()
+			ci.destroying = false
+			ctx.Unlock()
 		}()
 		return nil
 	}
@@ -1328,6 +1669,49 @@ func (ctx *executionContext) destroyCluster(ci *clusterInstance, sendUpdate, for
 	return err
 }
 
+// instanceCount - number of cluster instances required to run testCount tests, limited by maximum.
+func (ctx *executionContext) instanceCount(testCount, maximum int) int {
+	testsPerInstance := int(math.Min(float64(ctx.cloudTestConfig.TestsPerClusterInstance), 20))
+	return int(math.Ceil(math.Min(float64(testCount)/float64(testsPerInstance), float64(maximum))))
+}
+
+// resizeClusters - drop cluster instances not required for tasks left, instances are not started yet.
+func (ctx *executionContext) resizeClusters() {
+	ctx.Lock()
+	defer ctx.Unlock()
+
+	pending := map[*clustersGroup]int{}
+	for _, task := range ctx.tasks {
+		groups := task.clusters
+		if len(task.candidates) > 0 {
+			groups = task.candidates
+		}
+		for _, group := range groups {
+			pending[group]++
+		}
+	}
+	for _, group := range ctx.clusters {
+		count := int(math.Max(float64(ctx.instanceCount(pending[group], group.config.Instances)), 1))
+		if count == group.config.Instances {
+			continue
+		}
+		var instances []*clusterInstance
+		regular := 0
+		for _, ci := range group.instances {
+			if !ci.spare {
+				if regular == count {
+					continue
+				}
+				regular++
+			}
+			instances = append(instances, ci)
+		}
+		logrus.Infof("Reducing instances of '%s' cluster from %d to %d to run %d task(s) left", group.config.Name, group.config.Instances, count, pending[group])
+		group.config.Instances = count
+		group.instances = instances
+	}
+}
+
 func (ctx *executionContext) createClusters() error {
 	ctx.clusters = []*clustersGroup{}
 	clusterProviders, err := createClusterProviders(ctx.manager)
@@ -1351,12 +1735,19 @@ func (ctx *executionContext) createClusters() error {
 				tasks:     map[string]*testTask{},
 				completed: map[string]*testTask{},
 			}
-			testsPerInstance := int(math.Min(float64(ctx.cloudTestConfig.TestsPerClusterInstance), 20))
 			// initial value of cl.Instances is treated as allowed maximum
-			cl.Instances = int(math.Ceil(math.Min(float64(testCount)/float64(testsPerInstance), float64(cl.Instances))))
+			cl.Instances = ctx.instanceCount(testCount, cl.Instances)
 			logrus.Infof("Creating %d instances of '%s' cluster to run %d test(s)", cl.Instances, cl.Name, testCount)
-			for i := 0; i < cl.Instances; i++ {
+			if cl.WarmSpares > 0 {
+				logrus.Infof("Creating %d warm spare instances of '%s' cluster", cl.WarmSpares, cl.Name)
+			}
+			for i := 0; i < cl.Instances+cl.WarmSpares; i++ {
+				spare := i >= cl.Instances
 				cluster, err := provider.CreateCluster(cl, ctx.factory, ctx.manager, ctx.arguments.instanceOptions)
+				if err != nil && spare {
+					logrus.Warnf("Failed to create spare cluster instance, continue with %d spare(s). Error %v", i-cl.Instances, err)
+					break
+				}
 				if err != nil {
 					msg := fmt.Sprintf("Failed to create cluster instance. Error %v", err)
 					logrus.Errorf(msg)
@@ -1368,6 +1759,7 @@ func (ctx *executionContext) createClusters() error {
 					state:     clusterAdded,
 					id:        cluster.GetID(),
 					group:     group,
+					spare:     spare,
 				})
 			}
 			group.instances = instances
@@ -1429,7 +1821,7 @@ func (ctx *executionContext) shouldEnableCluster(cl *config.ClusterProviderConfi
 	for _, ex := range ctx.cloudTestConfig.Executions {
 		// accept empty Kind to make unit tests work
 		kindMatches := ex.Kind == "" || ex.Kind == cl.Kind
-		mightBeUsed := len(ex.ClusterSelector) == 0 || utils.Contains(ex.ClusterSelector, cl.Name)
+		mightBeUsed := matchesExecution(ex, cl)
 		if kindMatches && mightBeUsed && ex.TestsFound > 0 {
 			cl.Enabled = true
 			testCount = testCount + ex.TestsFound
@@ -1450,6 +1842,11 @@ func (ctx *executionContext) findTests() error {
 		if exec.Name == "" {
 			return errors.New("execution name should be specified")
 		}
+		for _, r := range exec.ClusterRequirements {
+			if err := r.Validate(); err != nil {
+				return errors.Wrapf(err, "invalid cluster requirements of execution %v", exec.Name)
+			}
+		}
 		if exec.Kind == "" || exec.Kind == "gotest" {
 			tests, err := ctx.findGoTest(exec)
 			if err != nil {
@@ -1459,6 +1856,12 @@ func (ctx *executionContext) findTests() error {
 		} else if exec.Kind == "shell" {
 			tests := ctx.findShellTest(exec)
 			ctx.appendTests(tests...)
+		} else if exec.Kind == "ginkgo" {
+			tests, err := ctx.findGinkgoTest(exec)
+			if err != nil {
+				return err
+			}
+			ctx.appendTests(tests...)
 		} else {
 			return errors.Errorf("unknown executon kind %v", exec.Kind)
 		}
@@ -1485,20 +1888,90 @@ func (ctx *executionContext) findShellTest(exec *config.Execution) []*model.Test
 	}
 }
 
-func (ctx *executionContext) findGoTest(executionConfig *config.Execution) ([]*model.TestEntry, error) {
+func (ctx *executionContext) findGinkgoTest(exec *config.Execution) ([]*model.TestEntry, error) {
 	st := time.Now()
 
-	logrus.Infof("Starting finding tests by source %v", executionConfig.Source)
+	logrus.Infof("Starting finding Ginkgo specs by source %v", exec.Source)
 
-	execTests, err := model.GetTestConfiguration(ctx.manager, executionConfig.PackageRoot, executionConfig.Source)
+	suite, err := ginkgo.Find(ctx.manager, exec.PackageRoot, exec.Source.Tags)
 	if err != nil {
-		logrus.Errorf("Failed during test lookup %v", err)
+		logrus.Errorf("Failed during Ginkgo specs lookup %v", err)
 		return nil, err
 	}
+	logrus.Infof("Ginkgo specs found: %v Elapsed: %v", len(suite.Tests), time.Since(st))
 
-	result, err := ctx.findGoSuites(executionConfig, execTests)
-	if err != nil {
-		return nil, errors.Wrapf(err, "an error during searching go suites")
+	filter := append(append([]string{}, exec.Source.Tests...), exec.OnlyRun...)
+	if len(filter) > 0 {
+		var specs []string
+		for _, spec := range suite.Tests {
+			if utils.Contains(filter, spec) {
+				specs = append(specs, spec)
+			}
+		}
+		logrus.Infof("Ginkgo specs after filtering: %v", len(specs))
+		suite.Tests = specs
+	}
+	if len(suite.Tests) == 0 {
+		return nil, nil
+	}
+
+	return []*model.TestEntry{
+		{
+			Name:            exec.Name,
+			Kind:            model.GinkgoTestKind,
+			Tags:            strings.Join(exec.Source.Tags, ","),
+			ExecutionConfig: exec,
+			Status:          model.StatusAdded,
+			Suite:           suite,
+		},
+	}, nil
+}
+
+func (ctx *executionContext) findGoTest(executionConfig *config.Execution) ([]*model.TestEntry, error) {
+	st := time.Now()
+
+	logrus.Infof("Starting finding tests by source %v", executionConfig.Source)
+
+	source := executionConfig.Source
+	packages := []string{""}
+	if len(source.Packages) > 0 {
+		var err error
+		if packages, err = model.ListPackages(ctx.manager, executionConfig.PackageRoot, source.Packages, source.Tags); err != nil {
+			logrus.Errorf("Failed during packages lookup %v", err)
+			return nil, err
+		}
+		// Tests could be defined in any of packages, so they are filtered when all packages are processed.
+		source.Tests = nil
+	}
+
+	var result, execTests []*model.TestEntry
+	for _, pkg := range packages {
+		pkgTests, err := model.GetTestConfiguration(ctx.manager, filepath.Join(executionConfig.PackageRoot, pkg), source)
+		if err != nil {
+			logrus.Errorf("Failed during test lookup %v", err)
+			return nil, err
+		}
+
+		suiteTests, err := ctx.findGoSuites(executionConfig, pkg, pkgTests)
+		if err != nil {
+			return nil, errors.Wrapf(err, "an error during searching go suites")
+		}
+		result = append(result, suiteTests...)
+
+		if err = ctx.findSubtests(executionConfig, pkg, pkgTests); err != nil {
+			return nil, errors.Wrapf(err, "an error during searching subtests")
+		}
+		for _, t := range pkgTests {
+			t.Package = pkg
+			execTests = append(execTests, t)
+		}
+	}
+
+	if len(source.Packages) > 0 && len(executionConfig.Source.Tests) > 0 {
+		var err error
+		if result, execTests, err = filterTestsByName(executionConfig.Source.Tests, result, execTests); err != nil {
+			return nil, err
+		}
 	}
 
 	testCount := len(execTests)
@@ -1510,7 +1983,6 @@ func (ctx *executionContext) findGoTest(executionConfig *config.Execution) ([]*m
 
 	filteredTestsCount := 0
 	for _, t := range execTests {
-		t.Kind = model.GoTestKind
 		t.ExecutionConfig = executionConfig
 		if len(executionConfig.OnlyRun) == 0 || utils.Contains(executionConfig.OnlyRun, t.Name) {
 			result = append(result, t)
@@ -1526,8 +1998,31 @@ func (ctx *executionContext) findGoTest(executionConfig *config.Execution) ([]*m
 	return result, nil
 }
 
-func (ctx *executionContext) findGoSuites(execution *config.Execution, allTests map[string]*model.TestEntry) ([]*model.TestEntry, error) {
-	testSuites, err := suites.Find(execution.PackageRoot)
+// filterTestsByName - keep suites and tests with given names, it is an error if any name is not found.
+func filterTestsByName(names []string, testSuites, tests []*model.TestEntry) (filteredSuites, filteredTests []*model.TestEntry, err error) {
+	found := map[string]bool{}
+	for _, t := range testSuites {
+		if utils.Contains(names, t.Name) {
+			found[t.Name] = true
+			filteredSuites = append(filteredSuites, t)
+		}
+	}
+	for _, t := range tests {
+		if utils.Contains(names, t.Name) {
+			found[t.Name] = true
+			filteredTests = append(filteredTests, t)
+		}
+	}
+	for _, n := range names {
+		if !found[n] {
+			return nil, nil, errors.Errorf("test %v not found", n)
+		}
+	}
+	return filteredSuites, filteredTests, nil
+}
+
+func (ctx *executionContext) findGoSuites(execution *config.Execution, pkg string, allTests map[string]*model.TestEntry) ([]*model.TestEntry, error) {
+	testSuites, err := suites.FindPackage(filepath.Join(execution.PackageRoot, pkg), execution.Source.Tags)
 	if err != nil {
 		return nil, err
 	}
@@ -1540,6 +2035,7 @@ func (ctx *executionContext) findGoSuites(execution *config.Execution, allTests
 		result = append(result, &model.TestEntry{
 			Name:            s.Name,
 			Tags:            strings.Join(execution.Source.Tags, ","),
+			Package:         pkg,
 			Kind:            model.SuiteTestKind,
 			Suite:           s,
 			ExecutionConfig: execution,
@@ -1548,7 +2044,31 @@ func (ctx *executionContext) findGoSuites(execution *config.Execution, allTests
 	return result, nil
 }
 
+// findSubtests - turn tests with table-driven subtests into subtests entries, so subtests are split between cluster instances.
+func (ctx *executionContext) findSubtests(execution *config.Execution, pkg string, allTests map[string]*model.TestEntry) error {
+	if !execution.Source.Subtests {
+		return nil
+	}
+	subtests, err := suites.FindSubtests(filepath.Join(execution.PackageRoot, pkg))
+	if err != nil {
+		return err
+	}
+	for name, t := range allTests {
+		if names := subtests[name]; len(names) > 1 {
+			t.Kind = model.SubtestsTestKind
+			t.Suite = &model.Suite{
+				Name:  name,
+				Tests: names,
+			}
+		}
+	}
+	return nil
+}
+
 func buildClusterSuiteName(clusters []*clustersGroup) string {
+	if len(clusters) == 0 {
+		return noClustersSuiteName
+	}
 	var clusterProviderNames = make([]string, len(clusters))
 	for i := 0; i < len(clusters); i++ {
 		clusterProviderNames[i] = clusters[i].config.Name
@@ -1629,13 +2149,15 @@ func (ctx *executionContext) generateJUnitReportFile() (*reporting.JUnitFile, er
 	summarySuite.Tests = totalTests
 	ctx.report.Suites = append(ctx.report.Suites, summarySuite)
 
-	output, err := xml.MarshalIndent(ctx.report, "  ", "    ")
-	if err != nil {
-		logrus.Errorf("failed to store JUnit xml report: %v\n", err)
-	}
 	if ctx.cloudTestConfig.Reporting.JUnitReportFile != "" {
-		ctx.manager.AddFile(ctx.cloudTestConfig.Reporting.JUnitReportFile, output)
+		output := &bytes.Buffer{}
+		if err := reporting.NewJUnitReporter().Write(output, ctx.report); err != nil {
+			logrus.Errorf("failed to store JUnit xml report: %v\n", err)
+		}
+		ctx.manager.AddFile(ctx.cloudTestConfig.Reporting.JUnitReportFile, output.Bytes())
 	}
+	ctx.generateReporterFiles()
+	ctx.generateHTMLReportFile()
 	if totalFailures > 0 {
 		return ctx.report, errors.Errorf("there is failed tests %v", totalFailures)
 	}
@@ -1688,11 +2210,12 @@ func (ctx *executionContext) generateReportSuiteByTestTasks(
 		var subTestsCount, subFailuresCount int
 		var subDuration time.Duration
 
+		quarantined := ctx.isQuarantined(test.test)
 		switch test.test.Kind {
 		case model.GoTestKind, model.ShellTestKind:
-			subTestsCount, subDuration, subFailuresCount = ctx.generateTestCaseReport(test, suite)
-		case model.SuiteTestKind:
-			subTestsCount, subDuration, subFailuresCount = ctx.generateTestSuiteReport(test, suite)
+			subTestsCount, subDuration, subFailuresCount = ctx.generateTestCaseReport(test, suite, quarantined)
+		case model.SuiteTestKind, model.GinkgoTestKind, model.SubtestsTestKind:
+			subTestsCount, subDuration, subFailuresCount = ctx.generateTestSuiteReport(test, suite, quarantined)
 		}
 
 		testsCount += subTestsCount
@@ -1705,8 +2228,14 @@ func (ctx *executionContext) generateReportSuiteByTestTasks(
 
 func (ctx *executionContext) getAllTestTasksGroupedByExecutions() map[string][]*testTask {
 	var executionsTests = make(map[string][]*testTask)
+	// Pending task with cluster requirements is assigned to every matching cluster group.
+	reported := map[*testTask]bool{}
 	for _, cluster := range ctx.clusters {
 		for _, test := range cluster.tasks {
+			if reported[test] {
+				continue
+			}
+			reported[test] = true
 			execName := test.test.ExecutionConfig.Name
 			executionsTests[execName] = append(executionsTests[execName], test)
 		}
@@ -1715,6 +2244,10 @@ func (ctx *executionContext) getAllTestTasksGroupedByExecutions() map[string][]*
 			executionsTests[execName] = append(executionsTests[execName], test)
 		}
 	}
+	for _, test := range ctx.unsatisfied {
+		execName := test.test.ExecutionConfig.Name
+		executionsTests[execName] = append(executionsTests[execName], test)
+	}
 	return executionsTests
 }
 
@@ -1743,6 +2276,7 @@ func (ctx *executionContext) generateClusterFailedReportEntry(instID string, exe
 func (ctx *executionContext) generateTestSuiteReport(
 	test *testTask,
 	parentSuite *reporting.Suite,
+	quarantined bool,
 ) (testsCount int, duration time.Duration, failuresCount int) {
 	suite := &reporting.Suite{
 		Name:  test.test.Suite.Name,
@@ -1756,19 +2290,42 @@ func (ctx *executionContext) generateTestSuiteReport(
 		tests = suites.SkipSuite(test.test)
 	default:
 		var err error
-		if tests, err = suites.SplitSuite(test.test, ctx.manager, test.clusterTaskID); err != nil {
+		if test.test.Kind == model.GinkgoTestKind {
+			tests, err = ginkgo.SplitSpecs(test.test, ctx.manager, test.clusterTaskID)
+		} else {
+			tests, err = suites.SplitSuite(test.test, ctx.manager, test.clusterTaskID)
+		}
+		if err != nil {
 			logrus.Fatalf("error: %+v", err)
 		}
 	}
 
+	classname := test.test.Suite.Name
+	if test.test.Package != "" {
+		classname = test.test.Package + "." + classname
+	}
+	nestedCount := 0
 	for _, testEntry := range tests {
 		_, _, subFailuresCount := ctx.generateTestCaseReport(&testTask{
 			test:             testEntry,
 			clusters:         test.clusters,
 			clusterInstances: test.clusterInstances,
 			clusterTaskID:    test.clusterTaskID,
-		}, suite)
+		}, suite, quarantined)
 		suite.Failures += subFailuresCount
+		if test.test.Kind != model.GinkgoTestKind && suites.IsNestedTest(testEntry) {
+			nestedCount++
+		}
+	}
+	for _, testCase := range suite.TestCases {
+		testCase.Classname = classname
+	}
+	// Nested subtests are reported as separate test cases, suite setup and teardown are not tests.
+	suite.Tests += nestedCount
+	if isFlaky(test.test) {
+		for _, testCase := range suite.TestCases {
+			testCase.Flaky = testCase.Failure == nil && testCase.SkipMessage == nil
+		}
 	}
 	parentSuite.Suites = append(parentSuite.Suites, suite)
 
@@ -1778,25 +2335,34 @@ func (ctx *executionContext) generateTestSuiteReport(
 func (ctx *executionContext) generateTestCaseReport(
 	test *testTask,
 	suite *reporting.Suite,
+	quarantined bool,
 ) (testsCount int, duration time.Duration, failuresCount int) {
 	testCase := &reporting.TestCase{
-		Name:    test.test.Name,
-		Time:    fmt.Sprintf("%v", test.test.Duration.Seconds()),
-		Cluster: test.clusterTaskID,
+		Classname: test.test.Package,
+		Name:      test.test.Name,
+		Time:      fmt.Sprintf("%v", test.test.Duration.Seconds()),
+		Cluster:   test.clusterTaskID,
 	}
 
 	switch test.test.Status {
+	case model.StatusSuccess:
+		// Every failed attempt of test passed on retry is reported with its output.
+		for idx := range test.test.Executions {
+			ex := &test.test.Executions[idx]
+			if ex.Status == model.StatusSuccess {
+				continue
+			}
+			testCase.FlakyFailures = append(testCase.FlakyFailures, &reporting.Failure{
+				Type:     "ERROR",
+				Message:  fmt.Sprintf("Execution attempt %v is %v on %v", idx, statusName(ex.Status), strings.Join(ex.ClusterInstances, ",")),
+				Contents: executionOutput(idx, ex),
+			})
+		}
 	case model.StatusFailed, model.StatusTimeout:
 		message := fmt.Sprintf("Test execution failed %v", test.test.Name)
 		result := strings.Builder{}
-		for idx, ex := range test.test.Executions {
-			lines, err := utils.ReadFile(ex.OutputFile)
-			if err != nil {
-				logrus.Errorf("Failed to read stored output %v", ex.OutputFile)
-				lines = []string{"Failed to read stored output:", ex.OutputFile, err.Error()}
-			}
-			result.WriteString(fmt.Sprintf("Execution attempt: %v Output file: %v\n", idx, ex.OutputFile))
-			result.WriteString(strings.Join(lines, "\n"))
+		for idx := range test.test.Executions {
+			result.WriteString(executionOutput(idx, &test.test.Executions[idx]))
 		}
 		testCase.Failure = &reporting.Failure{
 			Type:     "ERROR",
@@ -1828,11 +2394,27 @@ func (ctx *executionContext) generateTestCaseReport(
 			failuresCount++
 		}
 	}
+	testCase.Flaky = isFlaky(test.test)
+	// Failures of quarantined tests are reported, but not counted.
+	if quarantined {
+		testCase.Quarantined = true
+		failuresCount = 0
+	}
 	suite.TestCases = append(suite.TestCases, testCase)
 
 	return 1, test.test.Duration, failuresCount
 }
 
+// executionOutput - return stored output of execution attempt.
+func executionOutput(idx int, ex *model.TestEntryExecution) string {
+	lines, err := utils.ReadFile(ex.OutputFile)
+	if err != nil {
+		logrus.Errorf("Failed to read stored output %v", ex.OutputFile)
+		lines = []string{"Failed to read stored output:", ex.OutputFile, err.Error()}
+	}
+	return fmt.Sprintf("Execution attempt: %v Output file: %v\n", idx, ex.OutputFile) + strings.Join(lines, "\n")
+}
+
 func (ctx *executionContext) hasFailedCluster(task *testTask) bool {
 	for _, cg := range task.clusters {
 		failedInstances := 0
@@ -1911,8 +2493,10 @@ func createClusterProviders(manager execmanager.ExecutionManager) (map[string]pr
 	clusterProviders := map[string]providers.ClusterProvider{}
 
 	clusterProviderFactories := map[string]providers.ClusterProviderFunction{
-		"packet": packet.NewPacketClusterProvider,
-		"shell":  shell.NewShellClusterProvider,
+		"existing": existing.NewExistingClusterProvider,
+		"kind":     kind.NewKindClusterProvider,
+		"packet":   packet.NewPacketClusterProvider,
+		"shell":    shell.NewShellClusterProvider,
 	}
 
 	for key, factory := range clusterProviderFactories {
@@ -1976,6 +2560,18 @@ func initCmd(rootCmd *cloudTestCmd) {
 		"tags", "t", []string{}, "Run tests with given tag(s) only")
 	rootCmd.Flags().IntVarP(&rootCmd.cmdArguments.count,
 		"count", "", -1, "Execute only count of tests")
+	rootCmd.Flags().BoolVarP(&rootCmd.cmdArguments.resume,
+		"resume", "", false, "Resume interrupted run, skip tests already completed by previous run")
+	rootCmd.Flags().IntVarP(&rootCmd.cmdArguments.shardIndex,
+		"shard-index", "", 0, "An index of shard to execute, from 0 to shard-total - 1")
+	rootCmd.Flags().IntVarP(&rootCmd.cmdArguments.shardTotal,
+		"shard-total", "", 1, "A total number of shards to split tests between")
+	rootCmd.Flags().StringVarP(&rootCmd.cmdArguments.shardReport,
+		"shard-report", "", "", "A previous JUnit report to balance shards by test durations")
+	rootCmd.Flags().BoolVarP(&rootCmd.cmdArguments.dryRun,
+		"dry-run", "", false, "Print planned tasks, cluster instances and skipped tests without starting clusters")
+	rootCmd.Flags().StringVarP(&rootCmd.cmdArguments.dryRunFormat,
+		"dry-run-format", "", planFormatTable, "A format of planned tasks, 'table' or 'json'")
 
 	rootCmd.Flags().BoolVarP(&rootCmd.cmdArguments.instanceOptions.NoStop,
 		"noStop", "", false, "Skip stop operations")
@@ -1995,6 +2591,9 @@ func initCmd(rootCmd *cloudTestCmd) {
 		},
 	}
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(newMergeReportsCmd())
+	rootCmd.AddCommand(newQuarantineCmd())
+	rootCmd.AddCommand(newValidateCmd())
 }
 
 func initConfig() {
@@ -0,0 +1,249 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const (
+	historyVersion     = "1.0"
+	defaultHistorySize = 20
+)
+
+// testOutcome - an outcome of test in one run.
+type testOutcome struct {
+	Time   time.Time `json:"time"`
+	Status string    `json:"status"`
+	Flaky  bool      `json:"flaky,omitempty"`
+}
+
+// testHistory - a last outcomes of tests stored between runs by test key.
+type testHistory struct {
+	Version string                    `json:"version"`
+	Updated time.Time                 `json:"updated"`
+	Tests   map[string][]*testOutcome `json:"tests"`
+}
+
+// flakyTest - a statistics of test having both passed and failed outcomes in history.
+type flakyTest struct {
+	key      string
+	runs     int
+	failures int
+	flaky    int
+}
+
+func loadHistory(fileName string) (*testHistory, error) {
+	content, err := ioutil.ReadFile(filepath.Clean(fileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read history file %s", fileName)
+	}
+	history := &testHistory{}
+	if err = json.Unmarshal(content, history); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse history file %s", fileName)
+	}
+	if history.Version != historyVersion {
+		return nil, errors.Errorf("unsupported history file version %v", history.Version)
+	}
+	return history, nil
+}
+
+// originalName - return a name of test, tests split between cluster instances are named by original test.
+func originalName(test *model.TestEntry) string {
+	if test.SplitFrom != "" {
+		return test.SplitFrom
+	}
+	return test.Name
+}
+
+// historyKey - return a key of test in history and quarantine list.
+func historyKey(test *model.TestEntry) string {
+	return test.ExecutionConfig.Name + "/" + originalName(test)
+}
+
+// isFlaky - check if test is passed after failed attempts within same run.
+func isFlaky(test *model.TestEntry) bool {
+	if test.Status != model.StatusSuccess {
+		return false
+	}
+	for i := range test.Executions {
+		switch test.Executions[i].Status {
+		case model.StatusFailed, model.StatusTimeout, model.StatusRerunRequest, model.StatusRetryRequest:
+			return true
+		}
+	}
+	return false
+}
+
+// isQuarantined - check if test is listed in quarantine by its name or by 'execution/test' key, tests split between
+// cluster instances are checked by name of original test.
+func (ctx *executionContext) isQuarantined(test *model.TestEntry) bool {
+	testName := originalName(test)
+	for _, name := range ctx.cloudTestConfig.Quarantine {
+		if name == testName || test.ExecutionConfig != nil && name == test.ExecutionConfig.Name+"/"+testName {
+			return true
+		}
+	}
+	return false
+}
+
+// updateHistory - append outcomes of executed tests to history file, only last configured number of outcomes are kept.
+func (ctx *executionContext) updateHistory() {
+	file := ctx.cloudTestConfig.History.File
+	if file == "" {
+		return
+	}
+	history := &testHistory{}
+	if utils.FileExists(file) {
+		previous, err := loadHistory(file)
+		if err != nil {
+			logrus.Warnf("Previous history is ignored: %v", err)
+		} else {
+			history = previous
+		}
+	}
+	if history.Tests == nil {
+		history.Tests = map[string][]*testOutcome{}
+	}
+	size := ctx.cloudTestConfig.History.Size
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+
+	now := time.Now()
+	// Parts of split test are stored as one outcome, it is failed if any of parts is failed.
+	current := map[string]*testOutcome{}
+	var keys []string
+	ctx.RLock()
+	for _, task := range ctx.completed {
+		status := task.test.Status
+		if status != model.StatusSuccess && status != model.StatusFailed {
+			continue
+		}
+		key := historyKey(task.test)
+		outcome, ok := current[key]
+		if !ok {
+			outcome = &testOutcome{Time: now, Status: fmt.Sprintf("%v", statusName(status))}
+			current[key] = outcome
+			keys = append(keys, key)
+		} else if status == model.StatusFailed {
+			outcome.Status = fmt.Sprintf("%v", statusName(status))
+		}
+		outcome.Flaky = outcome.Flaky || isFlaky(task.test)
+	}
+	ctx.RUnlock()
+
+	for _, key := range keys {
+		outcomes := append(history.Tests[key], current[key])
+		if len(outcomes) > size {
+			outcomes = outcomes[len(outcomes)-size:]
+		}
+		history.Tests[key] = outcomes
+	}
+	updated := len(keys)
+
+	history.Version = historyVersion
+	history.Updated = now
+	content, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		logrus.Errorf("Failed to store history: %v", err)
+		return
+	}
+	if err = ioutil.WriteFile(file, content, 0600); err != nil {
+		logrus.Errorf("Failed to write history file %s: %v", file, err)
+		return
+	}
+	logrus.Infof("Outcomes of %d test(s) are stored into %s", updated, file)
+}
+
+// findFlakyTests - return tests having both passed and failed or flaky outcomes in history, most failing first.
+func findFlakyTests(history *testHistory, minRuns int) []*flakyTest {
+	var result []*flakyTest
+	for key, outcomes := range history.Tests {
+		stat := &flakyTest{key: key, runs: len(outcomes)}
+		for _, outcome := range outcomes {
+			switch {
+			case outcome.Status != "success":
+				stat.failures++
+			case outcome.Flaky:
+				stat.flaky++
+			}
+		}
+		if stat.runs < minRuns || stat.failures+stat.flaky == 0 || stat.failures == stat.runs {
+			continue
+		}
+		result = append(result, stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].failures+result[i].flaky != result[j].failures+result[j].flaky {
+			return result[i].failures+result[i].flaky > result[j].failures+result[j].flaky
+		}
+		return result[i].key < result[j].key
+	})
+	return result
+}
+
+// writeQuarantine - write a quarantine configuration section for flaky tests.
+func writeQuarantine(w io.Writer, tests []*flakyTest) error {
+	if _, err := fmt.Fprintln(w, "quarantine:"); err != nil {
+		return err
+	}
+	for _, t := range tests {
+		if _, err := fmt.Fprintf(w, "  - %q # %d of %d run(s) failed, %d flaky\n", t.key, t.failures, t.runs, t.flaky); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newQuarantineCmd() *cobra.Command {
+	minRuns := 2
+	cmd := &cobra.Command{
+		Use:   "quarantine [flags] history.json",
+		Short: "Print a quarantine list of flaky tests found in history file",
+		Long: `Find tests failing intermittently or passing only after retries in history file and print them as
+a 'quarantine' configuration section.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			history, err := loadHistory(args[0])
+			if err != nil {
+				logrus.Errorf("Failed to load history %v", err)
+				os.Exit(1)
+			}
+			if err = writeQuarantine(os.Stdout, findFlakyTests(history, minRuns)); err != nil {
+				logrus.Errorf("Failed to write quarantine list %v", err)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().IntVar(&minRuns, "min-runs", minRuns, "A minimal number of runs in history for test to be considered")
+	return cmd
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestIsFlaky(t *testing.T) {
+	test := &model.TestEntry{
+		Status:     model.StatusSuccess,
+		Executions: []model.TestEntryExecution{{Status: model.StatusSuccess}},
+	}
+	require.False(t, isFlaky(test))
+
+	test.Executions = append([]model.TestEntryExecution{{Status: model.StatusRerunRequest}}, test.Executions...)
+	require.True(t, isFlaky(test))
+
+	test.Status = model.StatusFailed
+	require.False(t, isFlaky(test))
+}
+
+func TestFindFlakyTests(t *testing.T) {
+	history := &testHistory{
+		Tests: map[string][]*testOutcome{
+			"simple/TestPass":  {{Status: "success"}, {Status: "success"}},
+			"simple/TestFail":  {{Status: "failed"}, {Status: "failed"}},
+			"simple/TestFlaky": {{Status: "success", Flaky: true}, {Status: "success"}},
+			"simple/TestSome":  {{Status: "failed"}, {Status: "success"}, {Status: "failed"}},
+			"simple/TestOnce":  {{Status: "failed"}},
+		},
+	}
+	tests := findFlakyTests(history, 2)
+	require.Len(t, tests, 2)
+	require.Equal(t, "simple/TestSome", tests[0].key)
+	require.Equal(t, "simple/TestFlaky", tests[1].key)
+
+	output := &bytes.Buffer{}
+	require.NoError(t, writeQuarantine(output, tests))
+	require.Equal(t, `quarantine:
+  - "simple/TestSome" # 2 of 3 run(s) failed, 0 flaky
+  - "simple/TestFlaky" # 0 of 2 run(s) failed, 1 flaky
+`, output.String())
+}
+
+func TestSplitSuiteHistory(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.History.File = filepath.Join(tmpDir, "history.json")
+	exec := &config.Execution{Name: "simple"}
+	splitTask := func(name string, status model.Status) *testTask {
+		return &testTask{test: &model.TestEntry{
+			Name:            name,
+			SplitFrom:       "TestSuite",
+			Status:          status,
+			ExecutionConfig: exec,
+		}}
+	}
+	ctx := &executionContext{cloudTestConfig: testConfig}
+
+	// Parts of split suite are stored as one outcome of original suite.
+	ctx.completed = []*testTask{splitTask("TestSuite1", model.StatusSuccess), splitTask("TestSuite2", model.StatusFailed)}
+	ctx.updateHistory()
+	ctx.completed = []*testTask{splitTask("TestSuite1", model.StatusSuccess), splitTask("TestSuite2", model.StatusSuccess)}
+	ctx.updateHistory()
+
+	history, err := loadHistory(testConfig.History.File)
+	require.NoError(t, err)
+	require.Len(t, history.Tests, 1)
+	require.Len(t, history.Tests["simple/TestSuite"], 2)
+	require.Equal(t, "failed", history.Tests["simple/TestSuite"][0].Status)
+	require.Equal(t, "success", history.Tests["simple/TestSuite"][1].Status)
+
+	tests := findFlakyTests(history, 2)
+	require.Len(t, tests, 1)
+	require.Equal(t, "simple/TestSuite", tests[0].key)
+
+	testConfig.Quarantine = []string{tests[0].key}
+	require.True(t, ctx.isQuarantined(splitTask("TestSuite2", model.StatusFailed).test))
+}
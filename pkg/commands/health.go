@@ -28,6 +28,11 @@ import (
 	"github.com/networkservicemesh/cloudtest/pkg/utils"
 )
 
+// healthCheckError - an error of failed health check probe.
+type healthCheckError struct {
+	error
+}
+
 // RunHealthChecks - Start goroutines with health check probes
 func RunHealthChecks(checkConfigs []*config.HealthCheckConfig, errCh chan<- error) {
 	for i := range checkConfigs {
@@ -45,7 +50,7 @@ func RunHealthChecks(checkConfigs []*config.HealthCheckConfig, errCh chan<- erro
 					builder := &strings.Builder{}
 					_, err := utils.RunCommand(timeoutCtx, cmd, "", func(s string) {}, bufio.NewWriter(builder), nil, nil, false)
 					if ready && err != nil {
-						errCh <- errors.Wrapf(errors.Errorf(config.Message), "health check probe failed")
+						errCh <- &healthCheckError{errors.Wrapf(errors.Errorf(config.Message), "health check probe failed")}
 						return
 					}
 				}
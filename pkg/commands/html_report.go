@@ -0,0 +1,182 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/reporting"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+// htmlLogTailLines - a number of last output lines to include into HTML report for every attempt.
+const htmlLogTailLines = 50
+
+// generateHTMLReportFile - write self-contained HTML report if it is configured.
+func (ctx *executionContext) generateHTMLReportFile() {
+	fileName := ctx.cloudTestConfig.Reporting.HTMLReportFile
+	if fileName == "" {
+		return
+	}
+	report := ctx.buildHTMLReport(filepath.Dir(path.Join(ctx.cloudTestConfig.ConfigRoot, fileName)))
+	content := &bytes.Buffer{}
+	if err := reporting.WriteHTMLReport(content, report); err != nil {
+		logrus.Errorf("Failed to store HTML report: %v", err)
+		return
+	}
+	ctx.manager.AddFile(fileName, content.Bytes())
+}
+
+func (ctx *executionContext) buildHTMLReport(reportDir string) *reporting.HTMLReport {
+	report := &reporting.HTMLReport{
+		Title:     "CloudTest report",
+		Generated: time.Now(),
+		Started:   ctx.startTime,
+		Finished:  time.Now(),
+	}
+
+	instanceSpans := map[string][]*reporting.HTMLSpan{}
+	ctx.RLock()
+	defer ctx.RUnlock()
+	for _, tasks := range ctx.getAllTestTasksGroupedByExecutions() {
+		for _, task := range tasks {
+			test := &reporting.HTMLTest{
+				Name:      task.test.Name,
+				Execution: task.test.ExecutionConfig.Name,
+				Cluster:   task.clusterTaskID,
+				Status:    fmt.Sprintf("%v", statusName(task.test.Status)),
+				Message:   task.test.SkipMessage,
+				Duration:  task.test.Duration,
+
+				Flaky:       isFlaky(task.test),
+				Quarantined: ctx.isQuarantined(task.test),
+			}
+			for _, dir := range task.test.ArtifactDirectories {
+				if utils.FileExists(dir) {
+					test.Artifacts = append(test.Artifacts, htmlLink(reportDir, dir))
+				}
+			}
+			for i := range task.test.Executions {
+				ex := &task.test.Executions[i]
+				test.Attempts = append(test.Attempts, htmlAttempt(reportDir, ex))
+				if ex.Started.IsZero() {
+					continue
+				}
+				for _, id := range ex.ClusterInstances {
+					instanceSpans[id] = append(instanceSpans[id], &reporting.HTMLSpan{
+						Kind:     fmt.Sprintf("%v", statusName(ex.Status)),
+						Label:    task.test.Name,
+						Started:  ex.Started,
+						Duration: ex.Duration,
+					})
+				}
+			}
+			report.Tests = append(report.Tests, test)
+		}
+	}
+	sort.SliceStable(report.Tests, func(i, j int) bool {
+		if report.Tests[i].Execution != report.Tests[j].Execution {
+			return report.Tests[i].Execution < report.Tests[j].Execution
+		}
+		return report.Tests[i].Name < report.Tests[j].Name
+	})
+
+	for _, group := range ctx.clusters {
+		for _, ci := range group.instances {
+			instance := &reporting.HTMLInstance{
+				ID:       ci.id,
+				Provider: group.config.Name,
+				State:    fromClusterState(ci),
+			}
+			for _, record := range ci.executions {
+				status := record.status.load()
+				if status == clusterAdded {
+					continue
+				}
+				span := &reporting.HTMLSpan{
+					Kind:     "start",
+					Label:    fmt.Sprintf("start #%d", record.attempt),
+					Started:  record.time,
+					Duration: record.duration,
+				}
+				if status != clusterReady {
+					span.Kind = "start-failed"
+					span.Label = fmt.Sprintf("crashed #%d", record.attempt)
+				}
+				instance.Spans = append(instance.Spans, span)
+			}
+			instance.Spans = append(instance.Spans, instanceSpans[ci.id]...)
+			// Tasks resumed from previous session could be started before current one.
+			for _, span := range instance.Spans {
+				if span.Started.Before(report.Started) {
+					report.Started = span.Started
+				}
+			}
+			report.Instances = append(report.Instances, instance)
+		}
+	}
+	return report
+}
+
+func htmlAttempt(reportDir string, ex *model.TestEntryExecution) *reporting.HTMLAttempt {
+	attempt := &reporting.HTMLAttempt{
+		Index:     ex.Retry,
+		Instances: strings.Join(ex.ClusterInstances, ", "),
+		Status:    fmt.Sprintf("%v", statusName(ex.Status)),
+		Started:   ex.Started,
+		Duration:  ex.Duration,
+	}
+	if ex.OutputFile == "" {
+		return attempt
+	}
+	attempt.Log = htmlLink(reportDir, ex.OutputFile)
+	lines, err := utils.ReadFile(ex.OutputFile)
+	if err != nil {
+		attempt.LogTail = fmt.Sprintf("Failed to read output: %v", err)
+		return attempt
+	}
+	if len(lines) > htmlLogTailLines {
+		lines = lines[len(lines)-htmlLogTailLines:]
+	}
+	attempt.LogTail = strings.Join(lines, "\n")
+	return attempt
+}
+
+// htmlLink - return a link to file relative to report folder, so report could be moved with results.
+func htmlLink(reportDir, fileName string) *reporting.HTMLLink {
+	link := &reporting.HTMLLink{
+		Name: filepath.Base(fileName),
+		Href: fileName,
+	}
+	absReport, err1 := filepath.Abs(reportDir)
+	absFile, err2 := filepath.Abs(fileName)
+	if err1 == nil && err2 == nil {
+		if rel, err := filepath.Rel(absReport, absFile); err == nil {
+			link.Href = filepath.ToSlash(rel)
+		}
+	}
+	return link
+}
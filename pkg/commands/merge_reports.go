@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/networkservicemesh/cloudtest/pkg/reporting"
+)
+
+const defaultMergedReport = "junit.xml"
+
+func newMergeReportsCmd() *cobra.Command {
+	output := defaultMergedReport
+	cmd := &cobra.Command{
+		Use:   "merge-reports [flags] report.xml...",
+		Short: "Merge JUnit reports of few shards into one report",
+		Long:  `Combine JUnit reports produced by few cloudtest shards into one report with summed totals and times.`,
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := mergeReports(output, args...); err != nil {
+				logrus.Errorf("Failed to merge reports %v", err)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", defaultMergedReport, "A merged report file")
+	return cmd
+}
+
+func mergeReports(output string, inputs ...string) error {
+	var files []*reporting.JUnitFile
+	for _, input := range inputs {
+		f, err := reporting.LoadJUnitFile(input)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+	}
+
+	content, err := xml.MarshalIndent(reporting.MergeJUnitFiles(files...), "  ", "    ")
+	if err != nil {
+		return errors.Wrap(err, "failed to store merged report")
+	}
+	if err = ioutil.WriteFile(output, content, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write merged report %s", output)
+	}
+	logrus.Infof("%d report(s) are merged into %s", len(inputs), output)
+	return nil
+}
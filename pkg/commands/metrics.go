@@ -0,0 +1,168 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/metrics"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+)
+
+// clusterStateLabels - cluster instance states exported as metric label values.
+var clusterStateLabels = []struct {
+	state clusterState
+	label string
+}{
+	{clusterAdded, "added"},
+	{clusterReady, "ready"},
+	{clusterBusy, "busy"},
+	{clusterStarting, "starting"},
+	{clusterStopping, "stopping"},
+	{clusterCrashed, "crashed"},
+	{clusterNotAvailable, "not_available"},
+	{clusterShutdown, "shutdown"},
+}
+
+// schedulerMetrics - collect metrics of current execution context on every scrape.
+type schedulerMetrics struct {
+	sync.Mutex
+	ctx    *executionContext
+	server *metrics.Server
+}
+
+// startMetrics - start metrics endpoint, metrics are empty until execution context is attached.
+func startMetrics(listen string) (*schedulerMetrics, error) {
+	m := &schedulerMetrics{}
+	server, err := metrics.NewServer(listen, m.collect)
+	if err != nil {
+		return nil, err
+	}
+	m.server = server
+	return m, nil
+}
+
+func (m *schedulerMetrics) attach(ctx *executionContext) {
+	if m == nil {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.ctx = ctx
+}
+
+func (m *schedulerMetrics) close() {
+	if m == nil {
+		return
+	}
+	if err := m.server.Close(); err != nil {
+		logrus.Errorf("Failed to stop metrics endpoint: %v", err)
+	}
+}
+
+func (m *schedulerMetrics) collect() []*metrics.Family {
+	var (
+		instances = metrics.NewFamily("cloudtest_cluster_instances",
+			"Number of cluster instances per provider and state.", metrics.Gauge)
+		tasks = metrics.NewFamily("cloudtest_tasks",
+			"Number of pending, running and completed tasks.", metrics.Gauge)
+		startAttempts = metrics.NewFamily("cloudtest_cluster_start_attempts_total",
+			"Number of cluster instance start attempts.", metrics.Counter)
+		crashes = metrics.NewFamily("cloudtest_cluster_crashes_total",
+			"Number of cluster instance crashes, including failed starts.", metrics.Counter)
+		notAvailable = metrics.NewFamily("cloudtest_cluster_not_available_total",
+			"Number of times cluster instances are marked as not available.", metrics.Counter)
+		retests = metrics.NewFamily("cloudtest_retests_total",
+			"Number of test re-runs requested.", metrics.Counter)
+		healthChecks = metrics.NewFamily("cloudtest_health_check_failures_total",
+			"Number of health check failures.", metrics.Counter)
+		results = metrics.NewFamily("cloudtest_test_results_total",
+			"Number of completed tests per execution and status.", metrics.Counter)
+		startTime = metrics.NewFamily("cloudtest_cluster_start_duration_seconds",
+			"Duration of cluster instance starts.", metrics.Histogram)
+		testDuration = metrics.NewFamily("cloudtest_test_duration_seconds",
+			"Duration of completed tests.", metrics.Histogram)
+	)
+	families := []*metrics.Family{
+		instances, tasks, startAttempts, crashes, notAvailable, retests, healthChecks, results, startTime, testDuration,
+	}
+
+	m.Lock()
+	ctx := m.ctx
+	m.Unlock()
+	if ctx == nil {
+		return families
+	}
+
+	ctx.RLock()
+	defer ctx.RUnlock()
+
+	for _, group := range ctx.clusters {
+		provider := metrics.Label{Name: "provider", Value: group.config.Name}
+		counts := map[clusterState]int{}
+		for _, ci := range group.instances {
+			counts[ci.state.load()]++
+			for _, record := range ci.executions {
+				if record.status.load() == clusterAdded {
+					// Start is still in progress.
+					continue
+				}
+				startTime.Observe(record.duration.Seconds(), provider)
+			}
+		}
+		for _, s := range clusterStateLabels {
+			instances.Add(float64(counts[s.state]), provider, metrics.Label{Name: "state", Value: s.label})
+		}
+		startAttempts.Add(float64(group.startAttempts), provider)
+		crashes.Add(float64(group.crashes), provider)
+		notAvailable.Add(float64(group.notAvailable), provider)
+	}
+
+	tasks.Add(float64(len(ctx.tasks)), metrics.Label{Name: "state", Value: "pending"})
+	tasks.Add(float64(len(ctx.running)), metrics.Label{Name: "state", Value: "running"})
+	tasks.Add(float64(len(ctx.completed)), metrics.Label{Name: "state", Value: "completed"})
+
+	retests.Add(float64(ctx.retestCount))
+	healthChecks.Add(float64(ctx.healthFailures))
+
+	for _, task := range ctx.completed {
+		execution := metrics.Label{Name: "execution", Value: task.test.ExecutionConfig.Name}
+		results.Add(1, execution, metrics.Label{Name: "status", Value: statusLabel(task.test.Status)})
+		if task.test.Status == model.StatusSuccess || task.test.Status == model.StatusFailed {
+			testDuration.Observe(task.test.Duration.Seconds(), execution)
+		}
+	}
+	return families
+}
+
+func statusLabel(status model.Status) string {
+	switch status {
+	case model.StatusSuccess:
+		return "success"
+	case model.StatusFailed:
+		return "failed"
+	case model.StatusTimeout:
+		return "timeout"
+	case model.StatusSkipped:
+		return "skipped"
+	case model.StatusSkippedSinceNoClusters:
+		return "skipped_no_clusters"
+	}
+	return "unknown"
+}
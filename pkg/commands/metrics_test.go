@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/metrics"
+	"github.com/networkservicemesh/cloudtest/pkg/tests"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func scrapeMetrics(t *testing.T, m *schedulerMetrics) string {
+	resp, err := http.Get("http://" + m.server.Addr() + metrics.Path)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	content, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestSchedulerMetrics(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	m, err := startMetrics("127.0.0.1:0")
+	require.NoError(t, err)
+	defer m.close()
+
+	// Families are exported before execution is started.
+	require.Contains(t, scrapeMetrics(t, m), "# TYPE cloudtest_cluster_instances gauge")
+
+	testConfig := createResumeConfig(tmpDir, "TestPass1", "TestFail1")
+	testConfig.Executions[0].Source.Tags = []string{"passed", "failed"}
+	_, err = PerformTesting(testConfig, &tests.TestValidationFactory{}, &Arguments{metrics: m})
+	require.Error(t, err)
+
+	content := scrapeMetrics(t, m)
+	// Instance is destroyed at the end of run, destroyed instances are kept in crashed state.
+	require.Contains(t, content, `cloudtest_cluster_instances{provider="a_provider",state="crashed"} 1`)
+	for _, state := range []string{"added", "ready", "busy", "starting", "stopping", "not_available", "shutdown"} {
+		require.Contains(t, content, `cloudtest_cluster_instances{provider="a_provider",state="`+state+`"} 0`)
+	}
+	require.Contains(t, content, `cloudtest_cluster_start_attempts_total{provider="a_provider"} 1`)
+	require.Contains(t, content, `cloudtest_cluster_crashes_total{provider="a_provider"} 0`)
+	require.Contains(t, content, `cloudtest_cluster_not_available_total{provider="a_provider"} 0`)
+	require.Contains(t, content, `cloudtest_tasks{state="completed"} 2`)
+	require.Contains(t, content, `cloudtest_tasks{state="pending"} 0`)
+	require.Contains(t, content, `cloudtest_test_results_total{execution="simple",status="success"} 1`)
+	require.Contains(t, content, `cloudtest_test_results_total{execution="simple",status="failed"} 1`)
+	require.Contains(t, content, `cloudtest_test_duration_seconds_count{execution="simple"} 2`)
+	require.Contains(t, content, `cloudtest_cluster_start_duration_seconds_bucket{provider="a_provider",le="+Inf"} 1`)
+}
+
+func TestSchedulerMetricsFailedStarts(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	m, err := startMetrics("127.0.0.1:0")
+	require.NoError(t, err)
+	defer m.close()
+
+	testConfig := createResumeConfig(tmpDir, "TestPass1")
+	testConfig.Providers[0].Scripts["start"] = "exit 1"
+	_, err = PerformTesting(testConfig, &tests.TestValidationFactory{}, &Arguments{metrics: m})
+	require.Error(t, err)
+
+	content := scrapeMetrics(t, m)
+	// Every failed start is a crash, instance is not available once retries are exhausted.
+	require.Contains(t, content, `cloudtest_cluster_instances{provider="a_provider",state="not_available"} 1`)
+	require.Contains(t, content, `cloudtest_cluster_start_attempts_total{provider="a_provider"} 2`)
+	require.Contains(t, content, `cloudtest_cluster_crashes_total{provider="a_provider"} 2`)
+	require.Contains(t, content, `cloudtest_cluster_not_available_total{provider="a_provider"} 1`)
+	require.Contains(t, content, `cloudtest_test_results_total{execution="simple",status="skipped_no_clusters"} 1`)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/suites/parse"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const binariesFolder = "binaries"
+
+// compileTests - build test binary once for every package root and tags of pending go test and suite tasks, so
+// package is not compiled by every task. Tasks of package failed to build are completed as failed with compiler output.
+func (ctx *executionContext) compileTests() {
+	ctx.Lock()
+
+	ctx.binaries = map[string]string{}
+	failures := map[string]string{}
+	compiled := map[string]bool{}
+
+	var tasks []*testTask
+	for _, task := range ctx.tasks {
+		if !canPrecompile(task.test) {
+			tasks = append(tasks, task)
+			continue
+		}
+		key := binaryKey(task.test)
+		if !compiled[key] {
+			compiled[key] = true
+			binary, err := ctx.compileTest(task.test, len(compiled))
+			if err != nil {
+				logrus.Errorf("Failed to build tests of %v: %v", task.test.PackageDir(), err)
+				failures[key] = err.Error()
+			} else if binary != "" {
+				ctx.binaries[key] = binary
+			}
+		}
+		if output, ok := failures[key]; ok {
+			ctx.failBuild(task, output)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	ctx.tasks = tasks
+	ctx.Unlock()
+
+	// Tasks failed to build are completed, they should not be executed again by resumed run.
+	if len(failures) > 0 {
+		ctx.checkpointState()
+	}
+}
+
+// testBinary - return precompiled binary for test, or empty string if test should be executed with go test.
+// Binaries are built before execution is started, so no lock is required.
+func (ctx *executionContext) testBinary(test *model.TestEntry) string {
+	if !canPrecompile(test) {
+		return ""
+	}
+	return ctx.binaries[binaryKey(test)]
+}
+
+func (ctx *executionContext) compileTest(test *model.TestEntry, index int) (string, error) {
+	root := test.PackageDir()
+	binary := filepath.Join(ctx.manager.AddFolder(binariesFolder, ""), fmt.Sprintf("%d-%s.test", index, filepath.Base(root)))
+	args := []string{"test", "-c", "-o", binary}
+	if test.Tags != "" {
+		args = append(args, "-tags", test.Tags)
+	}
+	args = append(args, ".")
+
+	st := time.Now()
+	cmd := exec.CommandContext(context.Background(), "go", args...)
+	cmd.Dir = root
+	output, err := cmd.CombinedOutput()
+	ctx.manager.AddLog(binariesFolder, "build", "go "+strings.Join(args, " ")+"\n"+string(output))
+	if err != nil {
+		return "", errors.Errorf("go %v: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	if !utils.FileExists(binary) {
+		// Package without tests, go test -c does not produce a binary.
+		return "", nil
+	}
+	logrus.Infof("Tests of %v are built in %v", root, time.Since(st))
+	return binary, nil
+}
+
+// failBuild - complete task as failed, since its package is failed to build.
+func (ctx *executionContext) failBuild(task *testTask, output string) {
+	fileName, file, err := ctx.manager.OpenFileTest(task.clusterTaskID, task.test.Name, "build")
+	if err != nil {
+		logrus.Errorf("Failed to store build output of %v: %v", task.test.Name, err)
+	} else {
+		_, _ = file.WriteString(buildFailureOutput(task.test, output))
+		_ = file.Close()
+	}
+	task.test.Status = model.StatusFailed
+	task.test.Started = time.Now()
+	task.test.Executions = append(task.test.Executions, model.TestEntryExecution{
+		OutputFile: fileName,
+		Status:     model.StatusFailed,
+		Started:    task.test.Started,
+	})
+	for ind, cl := range task.clusters {
+		delete(cl.tasks, task.test.Key)
+		if ind == 0 {
+			cl.completed[task.test.Key] = task
+		}
+	}
+	ctx.completed = append(ctx.completed, task)
+	if !ctx.isQuarantined(task.test) {
+		ctx.failedTestsCount++
+	}
+}
+
+// buildFailureOutput - return an output of failed build, for suites every suite test is reported failed in go test JSON
+// format, so suite report contains compiler output for every test.
+func buildFailureOutput(test *model.TestEntry, output string) string {
+	if test.Suite == nil {
+		return output
+	}
+	result := strings.Builder{}
+	now := time.Now()
+	for _, name := range test.Suite.Tests {
+		for _, event := range []*parse.TestEvent{
+			{Time: now, Action: "run", Test: test.Suite.Name + "/" + name},
+			{Time: now, Action: "output", Test: test.Suite.Name + "/" + name, Output: output},
+			{Time: now, Action: "fail", Test: test.Suite.Name + "/" + name},
+		} {
+			line, _ := json.Marshal(event)
+			_, _ = result.Write(line)
+			_ = result.WriteByte('\n')
+		}
+	}
+	return result.String()
+}
+
+func canPrecompile(test *model.TestEntry) bool {
+	switch test.Kind {
+	case model.GoTestKind, model.SuiteTestKind, model.SubtestsTestKind:
+		// A binary built on host could be incompatible with container image.
+		return test.ExecutionConfig.Image == ""
+	}
+	return false
+}
+
+func binaryKey(test *model.TestEntry) string {
+	return test.PackageDir() + "|" + test.Tags
+}
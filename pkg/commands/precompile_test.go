@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/suites"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestCompileTestsBuildFailure(t *testing.T) {
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	pkgDir := path.Join(tmpDir, "broken")
+	require.NoError(t, os.MkdirAll(pkgDir, os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(path.Join(pkgDir, "broken_test.go"),
+		[]byte("package broken\n\nfunc TestBroken(t *testing.T) {\n\tundefinedFunction()\n}\n"), os.ModePerm))
+
+	exec := &config.Execution{Name: "broken", PackageRoot: pkgDir}
+	group := &clustersGroup{
+		config:    &config.ClusterProviderConfig{Name: "a_provider"},
+		tasks:     map[string]*testTask{},
+		completed: map[string]*testTask{},
+	}
+	ctx := &executionContext{
+		cloudTestConfig: config.NewCloudTestConfig(),
+		manager:         execmanager.NewExecutionManager(path.Join(tmpDir, "results")),
+	}
+	for _, test := range []*model.TestEntry{
+		{Name: "TestBroken", Key: "TestBroken", Kind: model.GoTestKind},
+		{Name: "TestBrokenSuite", Key: "TestBrokenSuite", Kind: model.SuiteTestKind, Suite: &model.Suite{
+			Name:  "TestBrokenSuite",
+			Tests: []string{"TestFirst", "TestSecond"},
+		}},
+		{Name: "shell", Key: "shell", Kind: model.ShellTestKind},
+	} {
+		test.ExecutionConfig = exec
+		task := &testTask{test: test, clusters: []*clustersGroup{group}, clusterTaskID: "a_provider"}
+		group.tasks[test.Key] = task
+		ctx.tasks = append(ctx.tasks, task)
+	}
+
+	ctx.compileTests()
+
+	require.Len(t, ctx.tasks, 1)
+	require.Equal(t, "shell", ctx.tasks[0].test.Name)
+	require.Len(t, ctx.completed, 2)
+	require.Len(t, group.completed, 2)
+	require.Equal(t, 2, ctx.failedTestsCount)
+	require.Empty(t, ctx.testBinary(ctx.completed[0].test))
+
+	// Build failures are stored, so resumed run doesn't execute them again.
+	state, err := loadState(path.Join(tmpDir, "results"))
+	require.NoError(t, err)
+	require.Len(t, state.Tasks, 2)
+	for _, ts := range state.Tasks {
+		require.Equal(t, model.StatusFailed, ts.Status)
+	}
+
+	goTest := ctx.completed[0].test
+	require.Equal(t, model.StatusFailed, goTest.Status)
+	require.Len(t, goTest.Executions, 1)
+	lines, err := utils.ReadFile(goTest.Executions[0].OutputFile)
+	require.NoError(t, err)
+	require.Contains(t, strings.Join(lines, "\n"), "go test -c")
+
+	suiteTests, err := suites.SplitSuite(ctx.completed[1].test, ctx.manager, "a_provider")
+	require.NoError(t, err)
+	require.Len(t, suiteTests, 2)
+	for _, test := range suiteTests {
+		require.Equal(t, model.StatusFailed, test.Status)
+	}
+}
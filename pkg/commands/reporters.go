@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/reporting"
+)
+
+// checkReporters - check configured reporters, so wrong format is reported before any cluster is started.
+func (ctx *executionContext) checkReporters() error {
+	for _, r := range ctx.cloudTestConfig.Reporting.Reporters {
+		if _, err := reporting.NewReporter(r.Format); err != nil {
+			return err
+		}
+		if r.File == "" {
+			return errors.Errorf("report file is not specified for %v reporter", r.Format)
+		}
+	}
+	return nil
+}
+
+// generateReporterFiles - write report with every configured reporter.
+func (ctx *executionContext) generateReporterFiles() {
+	for _, r := range ctx.cloudTestConfig.Reporting.Reporters {
+		reporter, err := reporting.NewReporter(r.Format)
+		if err != nil {
+			logrus.Errorf("Failed to create %v reporter: %v", r.Format, err)
+			continue
+		}
+		content := &bytes.Buffer{}
+		if err := reporter.Write(content, ctx.report); err != nil {
+			logrus.Errorf("Failed to store %v report: %v", r.Format, err)
+			continue
+		}
+		ctx.manager.AddFile(r.File, content.Bytes())
+	}
+}
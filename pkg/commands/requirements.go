@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const noClustersSuiteName = "No matching clusters"
+
+// matchesExecution - check if cluster provider is selected by execution cluster selector and satisfies its requirements.
+func matchesExecution(exec *config.Execution, cl *config.ClusterProviderConfig) bool {
+	if len(exec.ClusterSelector) > 0 && !utils.Contains(exec.ClusterSelector, cl.Name) {
+		return false
+	}
+	return cl.MatchesRequirements(exec.ClusterRequirements)
+}
+
+func formatRequirements(requirements []*config.ClusterRequirement) string {
+	var result []string
+	for _, r := range requirements {
+		result = append(result, r.String())
+	}
+	return strings.Join(result, ", ")
+}
+
+// unsatisfiedRequirements - return a reason if execution requirements could not be satisfied by enabled clusters.
+func (ctx *executionContext) unsatisfiedRequirements(exec *config.Execution) string {
+	if len(exec.ClusterRequirements) == 0 {
+		return ""
+	}
+	if exec.ClusterCount > 1 {
+		if len(exec.ClusterSelector) == 0 {
+			return fmt.Sprintf("Cluster selector of %d clusters is required to match requirements: %s", exec.ClusterCount, formatRequirements(exec.ClusterRequirements))
+		}
+		// Every selected cluster should satisfy requirements.
+		for _, name := range exec.ClusterSelector {
+			for _, cl := range ctx.cloudTestConfig.Providers {
+				if cl.Name == name && !cl.MatchesRequirements(exec.ClusterRequirements) {
+					return fmt.Sprintf("Cluster %s does not satisfy requirements: %s", name, formatRequirements(exec.ClusterRequirements))
+				}
+			}
+		}
+		return ""
+	}
+	for _, cluster := range ctx.clusters {
+		if matchesExecution(exec, cluster.config) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("No enabled cluster satisfies requirements: %s", formatRequirements(exec.ClusterRequirements))
+}
+
+// skipUnsatisfiedTest - report test as skipped, since there is no cluster to execute it on.
+func (ctx *executionContext) skipUnsatisfiedTest(taskIndex int, test *model.TestEntry, reason string) {
+	logrus.Warnf("Skipping test %s: %s", test.Name, reason)
+	ctx.unsatisfied = append(ctx.unsatisfied, &testTask{
+		taskID: fmt.Sprintf("%d", taskIndex),
+		test: &model.TestEntry{
+			Kind:            test.Kind,
+			Name:            test.Name,
+			Key:             test.Name,
+			Tags:            test.Tags,
+			Package:         test.Package,
+			SplitFrom:       test.SplitFrom,
+			Status:          model.StatusSkipped,
+			SkipMessage:     reason,
+			Suite:           test.Suite,
+			ExecutionConfig: test.ExecutionConfig,
+			Executions:      []model.TestEntryExecution{},
+		},
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+)
+
+// shouldRetry - check if failed task has retries left, according to execution retry-on-failure.
+func (ctx *executionContext) shouldRetry(task *testTask) bool {
+	retries := 0
+	for i := range task.test.Executions {
+		if task.test.Executions[i].Status == model.StatusRetryRequest {
+			retries++
+		}
+	}
+	return retries < task.test.ExecutionConfig.RetryOnFailure
+}
+
+// isRetryOn - check if task is retried after failure on given cluster instance.
+func isRetryOn(task *testTask, ci *clusterInstance) bool {
+	if task.test.Status != model.StatusRetryRequest {
+		return false
+	}
+	for _, failed := range task.clusterInstances {
+		if failed == ci {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/events"
+)
+
+// openEventStream - start live events stream if it is configured.
+func (ctx *executionContext) openEventStream() error {
+	cfg := ctx.cloudTestConfig.Events
+	if cfg.File == "" && cfg.Listen == "" {
+		return nil
+	}
+	stream, err := events.NewStream(cfg.File, cfg.Listen)
+	if err != nil {
+		logrus.Errorf("Failed to open events stream: %v", err)
+		return err
+	}
+	ctx.eventStream = stream
+	return nil
+}
+
+func (ctx *executionContext) closeEventStream() {
+	if err := ctx.eventStream.Close(); err != nil {
+		logrus.Errorf("Failed to close events stream: %v", err)
+	}
+}
+
+func (ctx *executionContext) emitTaskEvent(kind events.Kind, task *testTask, message string) {
+	if ctx.eventStream == nil {
+		return
+	}
+	event := &events.Event{
+		Kind:            kind,
+		TaskID:          task.taskID,
+		Test:            task.test.Name,
+		Execution:       task.test.ExecutionConfig.Name,
+		ClusterInstance: task.clusterTaskID,
+		Status:          fmt.Sprintf("%v", statusName(task.test.Status)),
+		Message:         message,
+	}
+	if kind == events.TaskStarted {
+		event.Status = "running"
+	}
+	if !task.test.Started.IsZero() {
+		started := task.test.Started
+		event.Started = &started
+	}
+	if kind == events.TaskFinished {
+		event.Duration = task.test.Duration.Seconds()
+	}
+	ctx.eventStream.Emit(event)
+}
+
+func (ctx *executionContext) emitOnFail(task *testTask, ci *clusterInstance, err error) {
+	if ctx.eventStream == nil {
+		return
+	}
+	event := &events.Event{
+		Kind:            events.OnFail,
+		TaskID:          task.taskID,
+		Test:            task.test.Name,
+		Execution:       task.test.ExecutionConfig.Name,
+		ClusterInstance: ci.id,
+		Status:          "success",
+	}
+	if err != nil {
+		event.Status = "failed"
+		event.Message = err.Error()
+	}
+	ctx.eventStream.Emit(event)
+}
+
+func (ctx *executionContext) emitTermination(err error) {
+	kind := events.RunTerminated
+	if _, ok := err.(*healthCheckError); ok {
+		kind = events.HealthCheckFailed
+	}
+	ctx.eventStream.Emit(&events.Event{
+		Kind:    kind,
+		Message: err.Error(),
+	})
+}
+
+func (ctx *executionContext) emitRunFinished(err error) {
+	if ctx.eventStream == nil {
+		return
+	}
+	event := &events.Event{
+		Kind:     events.RunFinished,
+		Status:   "success",
+		Duration: time.Since(ctx.startTime).Seconds(),
+	}
+	ctx.RLock()
+	failed := ctx.failedTestsCount
+	ctx.RUnlock()
+	if failed > 0 {
+		event.Status = "failed"
+		event.Message = fmt.Sprintf("%d test(s) failed", failed)
+	}
+	if err != nil {
+		event.Status = "failed"
+		event.Message = err.Error()
+	}
+	ctx.eventStream.Emit(event)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const (
+	runStateFile    = "cloudtest-state.json"
+	runStateVersion = "1.0"
+)
+
+// runState - a state of run, stored to allow to resume interrupted run.
+type runState struct {
+	Version string       `json:"version"`
+	Updated time.Time    `json:"updated"`
+	Tasks   []*taskState `json:"tasks"`
+}
+
+// taskState - a stored state of completed task.
+type taskState struct {
+	Execution           string                     `json:"execution"`
+	Clusters            string                     `json:"clusters"`
+	Key                 string                     `json:"key"`
+	Name                string                     `json:"name"`
+	ClusterTaskID       string                     `json:"cluster-task-id"`
+	Status              model.Status               `json:"status"`
+	Duration            time.Duration              `json:"duration"`
+	SkipMessage         string                     `json:"skip-message,omitempty"`
+	Executions          []model.TestEntryExecution `json:"executions"`
+	ArtifactDirectories []string                   `json:"artifact-directories,omitempty"`
+}
+
+// taskStateKey - return a key to match task between sessions.
+func taskStateKey(execution, clusters, key string) string {
+	return execution + "/" + clusters + "/" + key
+}
+
+func isFinalStatus(status model.Status) bool {
+	return status == model.StatusSuccess || status == model.StatusFailed || status == model.StatusSkipped ||
+		status == model.StatusSkippedSinceNoClusters
+}
+
+// checkpointState - store all completed tasks into state file under configuration root.
+func (ctx *executionContext) checkpointState() {
+	state := &runState{
+		Version: runStateVersion,
+		Updated: time.Now(),
+	}
+	ctx.RLock()
+	for _, task := range ctx.completed {
+		if !isFinalStatus(task.test.Status) {
+			continue
+		}
+		state.Tasks = append(state.Tasks, &taskState{
+			Execution:           task.test.ExecutionConfig.Name,
+			Clusters:            makeTaskClusterID(task.clusters),
+			Key:                 task.test.Key,
+			Name:                task.test.Name,
+			ClusterTaskID:       task.clusterTaskID,
+			Status:              task.test.Status,
+			Duration:            task.test.Duration,
+			SkipMessage:         task.test.SkipMessage,
+			Executions:          task.test.Executions,
+			ArtifactDirectories: task.test.ArtifactDirectories,
+		})
+	}
+	ctx.RUnlock()
+
+	content, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		logrus.Errorf("Failed to store run state: %v", err)
+		return
+	}
+	ctx.manager.AddFile(runStateFile, content)
+}
+
+// loadState - load state of previous session from configuration root.
+func loadState(root string) (*runState, error) {
+	content, err := ioutil.ReadFile(filepath.Clean(path.Join(root, runStateFile)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read run state")
+	}
+	state := &runState{}
+	if err = json.Unmarshal(content, state); err != nil {
+		return nil, errors.Wrap(err, "failed to parse run state")
+	}
+	if state.Version != runStateVersion {
+		return nil, errors.Errorf("unsupported run state version %v", state.Version)
+	}
+	return state, nil
+}
+
+// restoreState - mark tasks completed in previous session as completed, they will not be executed again.
+func (ctx *executionContext) restoreState(state *runState) {
+	completed := map[string]*taskState{}
+	for _, ts := range state.Tasks {
+		completed[taskStateKey(ts.Execution, ts.Clusters, ts.Key)] = ts
+	}
+
+	ctx.Lock()
+	defer ctx.Unlock()
+
+	var tasks []*testTask
+	for _, task := range ctx.tasks {
+		ts, clusters := findTaskState(completed, task)
+		if ts == nil || !isFinalStatus(ts.Status) {
+			tasks = append(tasks, task)
+			continue
+		}
+		task.clusterTaskID = ts.ClusterTaskID
+		task.test.Status = ts.Status
+		task.test.Duration = ts.Duration
+		task.test.SkipMessage = ts.SkipMessage
+		task.test.Executions = ts.Executions
+		task.test.ArtifactDirectories = ts.ArtifactDirectories
+
+		for _, cl := range task.clusters {
+			delete(cl.tasks, task.test.Key)
+		}
+		for _, cl := range task.candidates {
+			delete(cl.tasks, task.test.Key)
+		}
+		task.clusters = clusters
+		task.clusters[0].completed[task.test.Key] = task
+		ctx.completed = append(ctx.completed, task)
+		if task.test.Status == model.StatusFailed && !ctx.isQuarantined(task.test) {
+			ctx.failedTestsCount++
+		}
+	}
+	logrus.Infof("Resumed %d completed task(s) from previous session, %d task(s) left", len(ctx.tasks)-len(tasks), len(tasks))
+	ctx.tasks = tasks
+}
+
+// findTaskState - find state of task completed in previous session and cluster groups it was completed on,
+// task with cluster requirements could be completed on any of matching groups.
+func findTaskState(completed map[string]*taskState, task *testTask) (*taskState, []*clustersGroup) {
+	options := [][]*clustersGroup{task.clusters}
+	for _, cl := range task.candidates {
+		options = append(options, []*clustersGroup{cl})
+	}
+	for _, clusters := range options {
+		if ts, ok := completed[taskStateKey(task.test.ExecutionConfig.Name, makeTaskClusterID(clusters), task.test.Key)]; ok {
+			return ts, clusters
+		}
+	}
+	return nil, nil
+}
+
+// resumeState - load state of previous session if it is requested.
+func (ctx *executionContext) resumeState() error {
+	if ctx.arguments == nil || !ctx.arguments.resume {
+		return nil
+	}
+	if !utils.FileExists(path.Join(ctx.cloudTestConfig.ConfigRoot, runStateFile)) {
+		logrus.Warnf("No run state found in %v, starting from scratch", ctx.cloudTestConfig.ConfigRoot)
+		return nil
+	}
+	state, err := loadState(ctx.cloudTestConfig.ConfigRoot)
+	if err != nil {
+		logrus.Errorf("Failed to resume run: %v", err)
+		return err
+	}
+	ctx.restoreState(state)
+	// Instances are created for all tests found, only tasks left are executed.
+	ctx.resizeClusters()
+	return nil
+}
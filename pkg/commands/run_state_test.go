@@ -0,0 +1,122 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/tests"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func createResumeConfig(root string, testNames ...string) *config.CloudTestConfig {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+	testConfig.ConfigRoot = root
+	createProvider(testConfig, "a_provider", "echo starting")
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "simple",
+		Timeout:     15,
+		PackageRoot: "../tests/sample",
+		Source: config.ExecutionSource{
+			Tags: []string{"passed"},
+		},
+		OnlyRun: testNames,
+	})
+	return testConfig
+}
+
+func TestResumeRun(t *testing.T) {
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	_, err = PerformTesting(createResumeConfig(tmpDir, "TestPass1"), &tests.TestValidationFactory{}, &Arguments{})
+	require.NoError(t, err)
+
+	state, err := loadState(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, state.Tasks, 1)
+	require.Equal(t, "TestPass1", state.Tasks[0].Name)
+	require.Equal(t, model.StatusSuccess, state.Tasks[0].Status)
+	require.Len(t, state.Tasks[0].Executions, 1)
+	require.FileExists(t, state.Tasks[0].Executions[0].OutputFile)
+
+	report, err := PerformTesting(createResumeConfig(tmpDir, "TestPass1", "TestPass2"), &tests.TestValidationFactory{}, &Arguments{resume: true})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, logKeeper.MessageCount("Resumed 1 completed task(s) from previous session, 1 task(s) left"))
+	require.Equal(t, 1, logKeeper.MessageCount("Starting TestPass1 on"))
+	require.Equal(t, 1, logKeeper.MessageCount("Starting TestPass2 on"))
+
+	require.Equal(t, 2, report.Suites[0].Tests)
+	require.Equal(t, 0, report.Suites[0].Failures)
+
+	// Output of first session is kept.
+	require.FileExists(t, state.Tasks[0].Executions[0].OutputFile)
+
+	state, err = loadState(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, state.Tasks, 2)
+}
+
+func TestResumeRunWithoutState(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	report, err := PerformTesting(createResumeConfig(tmpDir, "TestPass1"), &tests.TestValidationFactory{}, &Arguments{resume: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Suites[0].Tests)
+}
+
+func TestResumeRunReducesInstances(t *testing.T) {
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	createConfig := func(testNames ...string) *config.CloudTestConfig {
+		testConfig := createResumeConfig(tmpDir, testNames...)
+		testConfig.TestsPerClusterInstance = 1
+		testConfig.Providers[0].Instances = 4
+		return testConfig
+	}
+
+	_, err = PerformTesting(createConfig("TestPass1", "TestPass2", "TestPass3"), &tests.TestValidationFactory{}, &Arguments{})
+	require.NoError(t, err)
+
+	report, err := PerformTesting(createConfig("TestPass1", "TestPass2", "TestPass3", "TestPass4"), &tests.TestValidationFactory{}, &Arguments{resume: true})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, logKeeper.MessageCount("Creating 4 instances of 'a_provider' cluster to run 4 test(s)"))
+	require.Equal(t, 1, logKeeper.MessageCount("Reducing instances of 'a_provider' cluster from 4 to 1 to run 1 task(s) left"))
+	require.Equal(t, 1, logKeeper.MessageCount("Starting TestPass4 on a_provider-1"))
+	require.Equal(t, 4, report.Suites[0].Tests)
+	require.Equal(t, 0, report.Suites[0].Failures)
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/reporting"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const (
+	schedulingFIFO         = "fifo"
+	schedulingLongestFirst = "longest-first"
+
+	timingsVersion = "1.0"
+)
+
+// testTimings - a durations of tests stored between runs, in seconds by test name.
+type testTimings struct {
+	Version string             `json:"version"`
+	Updated time.Time          `json:"updated"`
+	Tests   map[string]float64 `json:"tests"`
+}
+
+func loadTimings(fileName string) (*testTimings, error) {
+	content, err := ioutil.ReadFile(filepath.Clean(fileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read timings file %s", fileName)
+	}
+	timings := &testTimings{}
+	if err = json.Unmarshal(content, timings); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse timings file %s", fileName)
+	}
+	if timings.Version != timingsVersion {
+		return nil, errors.Errorf("unsupported timings file version %v", timings.Version)
+	}
+	return timings, nil
+}
+
+// loadDurations - return known durations of tests from previous JUnit report and timings file,
+// timings file has a priority since it is updated after every run.
+func (ctx *executionContext) loadDurations() (map[string]time.Duration, error) {
+	durations := map[string]time.Duration{}
+	if report := ctx.cloudTestConfig.Timings.Report; report != "" {
+		f, err := reporting.LoadJUnitFile(report)
+		if err != nil {
+			return nil, err
+		}
+		for name, d := range f.TestDurations() {
+			durations[name] = d
+		}
+	}
+	if file := ctx.cloudTestConfig.Timings.File; file != "" && utils.FileExists(file) {
+		timings, err := loadTimings(file)
+		if err != nil {
+			return nil, err
+		}
+		for name, seconds := range timings.Tests {
+			durations[name] = time.Duration(seconds * float64(time.Second))
+		}
+	}
+	return durations, nil
+}
+
+// expectedDuration - return an expected duration of test, suite duration is a sum of its tests durations.
+func expectedDuration(test *model.TestEntry, durations map[string]time.Duration, defaultDuration time.Duration) time.Duration {
+	if d, ok := durations[test.Name]; ok {
+		return d
+	}
+	if test.Suite == nil || len(test.Suite.Tests) == 0 {
+		return defaultDuration
+	}
+	var result time.Duration
+	for _, name := range test.Suite.Tests {
+		if d, ok := durations[name]; ok {
+			result += d
+		} else {
+			result += defaultDuration
+		}
+	}
+	return result
+}
+
+// scheduleTasks - order tasks according to configured scheduling mode.
+func (ctx *executionContext) scheduleTasks() error {
+	switch ctx.cloudTestConfig.Scheduling {
+	case "", schedulingFIFO:
+		return nil
+	case schedulingLongestFirst:
+	default:
+		return errors.Errorf("unknown scheduling mode %v", ctx.cloudTestConfig.Scheduling)
+	}
+
+	durations, err := ctx.loadDurations()
+	if err != nil {
+		logrus.Errorf("Failed to load test timings: %v", err)
+		return err
+	}
+	defaultDuration := time.Duration(ctx.cloudTestConfig.Timings.DefaultDuration) * time.Second
+	if defaultDuration == 0 && len(durations) > 0 {
+		var total time.Duration
+		for _, d := range durations {
+			total += d
+		}
+		defaultDuration = total / time.Duration(len(durations))
+	}
+
+	ctx.Lock()
+	defer ctx.Unlock()
+	expected := map[*testTask]time.Duration{}
+	known := 0
+	for _, task := range ctx.tasks {
+		expected[task] = expectedDuration(task.test, durations, defaultDuration)
+		if _, ok := durations[task.test.Name]; ok {
+			known++
+		}
+	}
+	sort.SliceStable(ctx.tasks, func(i, j int) bool {
+		return expected[ctx.tasks[i]] > expected[ctx.tasks[j]]
+	})
+	logrus.Infof("Tasks are ordered longest first, %d of %d task(s) have known durations", known, len(ctx.tasks))
+	return nil
+}
+
+// updateTimings - store durations of executed tests into timings file, to be used by next runs.
+func (ctx *executionContext) updateTimings() {
+	file := ctx.cloudTestConfig.Timings.File
+	if file == "" {
+		return
+	}
+	timings := &testTimings{
+		Tests: map[string]float64{},
+	}
+	if utils.FileExists(file) {
+		previous, err := loadTimings(file)
+		if err != nil {
+			logrus.Warnf("Previous timings are ignored: %v", err)
+		} else {
+			timings = previous
+		}
+	}
+	if timings.Tests == nil {
+		timings.Tests = map[string]float64{}
+	}
+
+	updated := map[string]bool{}
+	ctx.RLock()
+	for _, task := range ctx.completed {
+		status := task.test.Status
+		if status != model.StatusSuccess && status != model.StatusFailed || task.test.Duration <= 0 {
+			continue
+		}
+		seconds := task.test.Duration.Seconds()
+		if updated[task.test.Name] && timings.Tests[task.test.Name] >= seconds {
+			continue
+		}
+		timings.Tests[task.test.Name] = seconds
+		updated[task.test.Name] = true
+	}
+	ctx.RUnlock()
+
+	timings.Version = timingsVersion
+	timings.Updated = time.Now()
+	content, err := json.MarshalIndent(timings, "", "  ")
+	if err != nil {
+		logrus.Errorf("Failed to store timings: %v", err)
+		return
+	}
+	if err = ioutil.WriteFile(file, content, 0600); err != nil {
+		logrus.Errorf("Failed to write timings file %s: %v", file, err)
+		return
+	}
+	logrus.Infof("Timings of %d test(s) are stored into %s", len(updated), file)
+}
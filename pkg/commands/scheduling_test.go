@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func createSchedulingContext(timingsFile string, names ...string) *executionContext {
+	ctx := &executionContext{
+		cloudTestConfig: config.NewCloudTestConfig(),
+	}
+	ctx.cloudTestConfig.Scheduling = schedulingLongestFirst
+	ctx.cloudTestConfig.Timings.File = timingsFile
+	for _, name := range names {
+		ctx.tasks = append(ctx.tasks, &testTask{
+			test: &model.TestEntry{Name: name},
+		})
+	}
+	return ctx
+}
+
+func taskNames(tasks []*testTask) []string {
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.test.Name)
+	}
+	return names
+}
+
+func TestLongestFirstScheduling(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	timingsFile := path.Join(tmpDir, "timings.json")
+	require.NoError(t, ioutil.WriteFile(timingsFile,
+		[]byte(`{"version": "1.0", "tests": {"TestShort": 1, "TestMedium": 10, "TestLong": 100}}`), 0600))
+
+	ctx := createSchedulingContext(timingsFile, "TestShort", "TestNew", "TestLong", "TestMedium")
+	require.NoError(t, ctx.scheduleTasks())
+	// TestNew has no history and gets an average duration.
+	require.Equal(t, []string{"TestLong", "TestNew", "TestMedium", "TestShort"}, taskNames(ctx.tasks))
+
+	ctx = createSchedulingContext(timingsFile, "TestShort", "TestNew", "TestLong", "TestMedium")
+	ctx.cloudTestConfig.Timings.DefaultDuration = 1000
+	require.NoError(t, ctx.scheduleTasks())
+	require.Equal(t, []string{"TestNew", "TestLong", "TestMedium", "TestShort"}, taskNames(ctx.tasks))
+
+	ctx = createSchedulingContext(timingsFile, "TestShort", "TestLong")
+	ctx.cloudTestConfig.Scheduling = ""
+	require.NoError(t, ctx.scheduleTasks())
+	require.Equal(t, []string{"TestShort", "TestLong"}, taskNames(ctx.tasks))
+
+	ctx.cloudTestConfig.Scheduling = "random"
+	require.Error(t, ctx.scheduleTasks())
+}
+
+func TestUpdateTimings(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	timingsFile := path.Join(tmpDir, "timings.json")
+	require.NoError(t, ioutil.WriteFile(timingsFile,
+		[]byte(`{"version": "1.0", "tests": {"TestOld": 5, "TestPass": 1}}`), 0600))
+
+	ctx := createSchedulingContext(timingsFile)
+	ctx.completed = []*testTask{
+		{test: &model.TestEntry{Name: "TestPass", Status: model.StatusSuccess, Duration: 3 * time.Second}},
+		{test: &model.TestEntry{Name: "TestFail", Status: model.StatusFailed, Duration: 2 * time.Second}},
+		{test: &model.TestEntry{Name: "TestSkip", Status: model.StatusSkipped}},
+	}
+	ctx.updateTimings()
+
+	timings, err := loadTimings(timingsFile)
+	require.NoError(t, err)
+	require.Equal(t, map[string]float64{
+		"TestOld":  5,
+		"TestPass": 3,
+		"TestFail": 2,
+	}, timings.Tests)
+}
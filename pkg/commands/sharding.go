@@ -0,0 +1,127 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/reporting"
+)
+
+type shardEntry struct {
+	test   *model.TestEntry
+	key    string
+	weight time.Duration
+}
+
+// shardTests - keep only tests of current shard, tests are split between shards deterministically,
+// so every cloudtest process with same configuration and shard total will select not crossing set of tests.
+func (ctx *executionContext) shardTests() error {
+	if ctx.arguments == nil || ctx.arguments.shardTotal <= 1 {
+		return nil
+	}
+	index, total := ctx.arguments.shardIndex, ctx.arguments.shardTotal
+	if index < 0 || index >= total {
+		return errors.Errorf("invalid shard index %d, should be in range [0, %d)", index, total)
+	}
+
+	var durations map[string]time.Duration
+	if ctx.arguments.shardReport != "" {
+		report, err := reporting.LoadJUnitFile(ctx.arguments.shardReport)
+		if err != nil {
+			logrus.Errorf("Failed to load report for sharding: %v", err)
+			return err
+		}
+		durations = report.TestDurations()
+	}
+
+	entries := shardEntries(ctx.tests, durations)
+	shards := make([]time.Duration, total)
+	counts := make([]int, total)
+	selected := map[*model.TestEntry]bool{}
+	for _, entry := range entries {
+		target := 0
+		for i := range shards {
+			if shards[i] < shards[target] || shards[i] == shards[target] && counts[i] < counts[target] {
+				target = i
+			}
+		}
+		shards[target] += entry.weight
+		counts[target]++
+		if target == index {
+			selected[entry.test] = true
+		}
+	}
+
+	var tests []*model.TestEntry
+	for _, exec := range ctx.cloudTestConfig.Executions {
+		exec.TestsFound = 0
+	}
+	for _, test := range ctx.tests {
+		if selected[test] {
+			tests = append(tests, test)
+			test.ExecutionConfig.TestsFound++
+		}
+	}
+	logrus.Infof("Shard %d of %d: selected %d of %d tests", index, total, len(tests), len(ctx.tests))
+	ctx.tests = tests
+	return nil
+}
+
+// shardEntries - return tests with their weights ordered by weight and key, so order does not depend on tests discovery.
+func shardEntries(tests []*model.TestEntry, durations map[string]time.Duration) []*shardEntry {
+	var average time.Duration
+	if len(durations) > 0 {
+		var total time.Duration
+		for _, d := range durations {
+			total += d
+		}
+		average = total / time.Duration(len(durations))
+	}
+
+	var entries []*shardEntry
+	for _, test := range tests {
+		entry := &shardEntry{
+			test:   test,
+			key:    test.ExecutionConfig.Name + "/" + test.Name,
+			weight: 1,
+		}
+		if test.Suite != nil {
+			entry.weight = time.Duration(len(test.Suite.Tests))
+		}
+		if durations != nil {
+			if d, ok := durations[test.Name]; ok {
+				entry.weight = d
+			} else {
+				entry.weight = average
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].weight != entries[j].weight {
+			return entries[i].weight > entries[j].weight
+		}
+		return entries[i].key < entries[j].key
+	})
+	return entries
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/reporting"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func createShardContext(index, total int, report string, testCount int) *executionContext {
+	ctx := &executionContext{
+		cloudTestConfig: config.NewCloudTestConfig(),
+		arguments: &Arguments{
+			shardIndex:  index,
+			shardTotal:  total,
+			shardReport: report,
+		},
+	}
+	exec := &config.Execution{Name: "simple"}
+	ctx.cloudTestConfig.Executions = append(ctx.cloudTestConfig.Executions, exec)
+	for i := 0; i < testCount; i++ {
+		ctx.tests = append(ctx.tests, &model.TestEntry{
+			Name:            fmt.Sprintf("Test%d", i),
+			ExecutionConfig: exec,
+		})
+	}
+	exec.TestsFound = testCount
+	return ctx
+}
+
+func shardNames(t *testing.T, index, total int, report string, testCount int) []string {
+	ctx := createShardContext(index, total, report, testCount)
+	require.NoError(t, ctx.shardTests())
+	require.Equal(t, len(ctx.tests), ctx.cloudTestConfig.Executions[0].TestsFound)
+	var names []string
+	for _, test := range ctx.tests {
+		names = append(names, test.Name)
+	}
+	return names
+}
+
+func TestShardTests(t *testing.T) {
+	const total, testCount = 3, 10
+
+	seen := map[string]bool{}
+	for index := 0; index < total; index++ {
+		names := shardNames(t, index, total, "", testCount)
+		require.Equal(t, names, shardNames(t, index, total, "", testCount))
+		require.True(t, len(names) == testCount/total || len(names) == testCount/total+1)
+		for _, name := range names {
+			require.False(t, seen[name], "test %s is selected by few shards", name)
+			seen[name] = true
+		}
+	}
+	require.Len(t, seen, testCount)
+
+	require.Error(t, createShardContext(3, total, "", testCount).shardTests())
+	require.Len(t, shardNames(t, 0, 1, "", testCount), testCount)
+}
+
+func TestShardTestsByDuration(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	report := &reporting.JUnitFile{
+		Suites: []*reporting.Suite{
+			{
+				Name: "All tests",
+				TestCases: []*reporting.TestCase{
+					{Name: "Test0", Time: "100"},
+					{Name: "Test1", Time: "10"},
+					{Name: "Test2", Time: "10"},
+					{Name: "Test3", Time: "10"},
+				},
+			},
+		},
+	}
+	content, err := xml.Marshal(report)
+	require.NoError(t, err)
+	reportFile := path.Join(tmpDir, "junit.xml")
+	require.NoError(t, ioutil.WriteFile(reportFile, content, 0600))
+
+	require.Equal(t, []string{"Test0"}, shardNames(t, 0, 2, reportFile, 4))
+	require.Equal(t, []string{"Test1", "Test2", "Test3"}, shardNames(t, 1, 2, reportFile, 4))
+}
@@ -0,0 +1,346 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/providers"
+	"github.com/networkservicemesh/cloudtest/pkg/reporting"
+)
+
+var (
+	executionKinds = []string{"", "gotest", "shell", "ginkgo"}
+	yamlLineError  = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+)
+
+// configProblem - a problem found in configuration file, line and column are 0 if position is not known.
+type configProblem struct {
+	file    string
+	line    int
+	column  int
+	message string
+}
+
+func (p *configProblem) String() string {
+	if p.line == 0 {
+		return fmt.Sprintf("%s: %s", p.file, p.message)
+	}
+	if p.column == 0 {
+		return fmt.Sprintf("%s:%d: %s", p.file, p.line, p.message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", p.file, p.line, p.column, p.message)
+}
+
+// configSource - a loaded configuration file with its YAML tree, used to find positions of problems.
+type configSource struct {
+	file   string
+	root   *yamlv3.Node
+	config *config.CloudTestConfig
+}
+
+type configLinter struct {
+	problems  []*configProblem
+	providers map[string]providers.ClusterProvider
+}
+
+func newValidateCmd() *cobra.Command {
+	configFile := defaultConfigFile
+	cmd := &cobra.Command{
+		Use:   "validate [flags]",
+		Short: "Validate configuration file without running tests",
+		Long: `Load configuration with imports applied, check unknown keys, provider configurations, executions
+against providers and retest patterns, print all problems found with file and line positions.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			problems := validateConfigFile(configFile)
+			for _, p := range problems {
+				fmt.Println(p)
+			}
+			if len(problems) > 0 {
+				logrus.Errorf("Configuration %s has %d problem(s)", configFile, len(problems))
+				os.Exit(1)
+			}
+			logrus.Infof("Configuration %s is valid", configFile)
+		},
+	}
+	cmd.Flags().StringVarP(&configFile, "config", "", defaultConfigFile, "Config file")
+	return cmd
+}
+
+// validateConfigFile - perform static checks of configuration file and its imports.
+func validateConfigFile(fileName string) []*configProblem {
+	l := &configLinter{}
+	root := l.load(fileName, config.NewCloudTestConfig())
+	if root == nil {
+		return l.problems
+	}
+	sources := []*configSource{root}
+	imports, err := resolveImports(root.config.Imports)
+	if err != nil {
+		l.report(root, lookupNode(root.root, "import"), "failed to resolve imports: %v", err)
+	}
+	for _, f := range imports {
+		if src := l.load(f, &config.CloudTestConfig{}); src != nil {
+			sources = append(sources, src)
+		}
+	}
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloudtest-validate")
+	if err == nil {
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		l.providers, err = createClusterProviders(execmanager.NewExecutionManager(tmpDir))
+	}
+	if err != nil {
+		l.report(root, nil, "failed to create cluster providers: %v", err)
+	}
+
+	l.checkGlobal(root)
+	providerNames := map[string]bool{}
+	var allProviders []*config.ClusterProviderConfig
+	for _, src := range sources {
+		for i, p := range src.config.Providers {
+			l.checkProvider(src, i, p, providerNames)
+			allProviders = append(allProviders, p)
+		}
+	}
+	executionNames := map[string]bool{}
+	for _, src := range sources {
+		for i, e := range src.config.Executions {
+			l.checkExecution(src, i, e, executionNames, providerNames, allProviders)
+		}
+	}
+
+	sort.SliceStable(l.problems, func(i, j int) bool {
+		a, b := l.problems[i], l.problems[j]
+		if a.file != b.file {
+			return a.file < b.file
+		}
+		return a.line < b.line
+	})
+	return l.problems
+}
+
+// load - read and strictly parse configuration file, unknown keys are reported but parsing continues.
+func (l *configLinter) load(fileName string, cfg *config.CloudTestConfig) *configSource {
+	src := &configSource{file: fileName, config: cfg}
+	content, err := ioutil.ReadFile(filepath.Clean(fileName))
+	if err != nil {
+		l.report(src, nil, "failed to read configuration file: %v", err)
+		return nil
+	}
+	src.root = &yamlv3.Node{}
+	if err = yamlv3.Unmarshal(content, src.root); err != nil {
+		l.reportYamlError(src, err.Error())
+		return nil
+	}
+	err = yaml.UnmarshalStrict(content, cfg)
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		for _, msg := range typeErr.Errors {
+			l.reportYamlError(src, msg)
+		}
+	} else if err != nil {
+		l.reportYamlError(src, err.Error())
+		return nil
+	}
+	return src
+}
+
+func (l *configLinter) reportYamlError(src *configSource, msg string) {
+	problem := &configProblem{file: src.file, message: msg}
+	if m := yamlLineError.FindStringSubmatch(msg); m != nil {
+		problem.line, _ = strconv.Atoi(m[1])
+		problem.message = m[2]
+	}
+	l.problems = append(l.problems, problem)
+}
+
+func (l *configLinter) report(src *configSource, node *yamlv3.Node, format string, args ...interface{}) {
+	problem := &configProblem{file: src.file, message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		problem.line, problem.column = node.Line, node.Column
+	}
+	l.problems = append(l.problems, problem)
+}
+
+func (l *configLinter) checkGlobal(src *configSource) {
+	cfg := src.config
+	for i, pattern := range cfg.RetestConfig.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			l.report(src, lookupNode(src.root, "retest", "pattern", i), "invalid retest pattern %q: %v", pattern, err)
+		}
+	}
+	switch cfg.Scheduling {
+	case "", schedulingFIFO, schedulingLongestFirst:
+	default:
+		l.report(src, lookupNode(src.root, "scheduling"), "unknown scheduling mode %v", cfg.Scheduling)
+	}
+	for i, r := range cfg.Reporting.Reporters {
+		if _, err := reporting.NewReporter(r.Format); err != nil {
+			l.report(src, lookupNode(src.root, "reporting", "reporters", i, "format"), "%v", err)
+		}
+		if r.File == "" {
+			l.report(src, lookupNode(src.root, "reporting", "reporters", i), "report file is not specified for %v reporter", r.Format)
+		}
+	}
+}
+
+func (l *configLinter) checkProvider(src *configSource, index int, p *config.ClusterProviderConfig, names map[string]bool) {
+	node := lookupNode(src.root, "providers", index)
+	if p.Name == "" {
+		l.report(src, node, "provider name should be specified")
+	} else if names[p.Name] {
+		l.report(src, lookupNode(node, "name"), "provider %v is already defined", p.Name)
+	}
+	names[p.Name] = true
+	if p.Instances <= 0 {
+		l.report(src, lookupNode(node, "instances"), "provider %v should have at least one instance", p.Name)
+	}
+	if l.providers == nil {
+		return
+	}
+	provider, ok := l.providers[p.Kind]
+	if !ok {
+		l.report(src, lookupNode(node, "kind"), "unknown kind '%v' of provider %v", p.Kind, p.Name)
+		return
+	}
+	if !p.Enabled {
+		// Environment of disabled provider is not required, for example secrets are not passed to CI job.
+		for _, name := range p.EnvCheck {
+			if os.Getenv(name) == "" {
+				logrus.Warnf("%s: environment variable %v of disabled provider %v is not specified", src.file, name, p.Name)
+			}
+		}
+		cfg := *p
+		cfg.EnvCheck = nil
+		p = &cfg
+	}
+	if err := provider.ValidateConfig(p); err != nil {
+		l.report(src, node, "invalid configuration of provider %v: %v", p.Name, err)
+	}
+}
+
+func (l *configLinter) checkExecution(src *configSource, index int, e *config.Execution, names, providerNames map[string]bool,
+	allProviders []*config.ClusterProviderConfig) {
+	node := lookupNode(src.root, "executions", index)
+	if e.Name == "" {
+		l.report(src, node, "execution name should be specified")
+	} else if names[e.Name] {
+		l.report(src, lookupNode(node, "name"), "execution %v is already defined", e.Name)
+	}
+	names[e.Name] = true
+
+	kindKnown := false
+	for _, k := range executionKinds {
+		kindKnown = kindKnown || k == e.Kind
+	}
+	if !kindKnown {
+		l.report(src, lookupNode(node, "kind"), "unknown kind '%v' of execution %v", e.Kind, e.Name)
+	}
+	if e.Kind == "shell" && e.Run == "" {
+		l.report(src, node, "shell execution %v should have 'run' script", e.Name)
+	}
+	if e.Image == "" && len(e.ImageOptions) > 0 {
+		l.report(src, lookupNode(node, "image-options"), "execution %v defines image-options without image", e.Name)
+	}
+
+	for i, name := range e.ClusterSelector {
+		if !providerNames[name] {
+			l.report(src, lookupNode(node, "cluster-selector", i), "execution %v selects unknown provider %v", e.Name, name)
+		}
+	}
+	count := e.ClusterCount
+	if count == 0 {
+		count = 1
+	}
+	if len(e.ClusterSelector) > 0 && count > len(e.ClusterSelector) {
+		l.report(src, lookupNode(node, "cluster-count"), "execution %v requires %d clusters, but selects only %d provider(s)",
+			e.Name, count, len(e.ClusterSelector))
+	}
+	if len(e.ClusterEnv) > 0 && len(e.ClusterEnv) != count {
+		l.report(src, lookupNode(node, "cluster-env"), "execution %v defines %d cluster-env variable(s) for %d cluster(s)",
+			e.Name, len(e.ClusterEnv), count)
+	}
+
+	valid := true
+	for i, r := range e.ClusterRequirements {
+		if err := r.Validate(); err != nil {
+			valid = false
+			l.report(src, lookupNode(node, "cluster-requirements", i), "invalid cluster requirement of execution %v: %v", e.Name, err)
+		}
+	}
+	if valid && len(e.ClusterRequirements) > 0 {
+		satisfied := false
+		for _, p := range allProviders {
+			satisfied = satisfied || p.MatchesRequirements(e.ClusterRequirements)
+		}
+		if !satisfied {
+			l.report(src, lookupNode(node, "cluster-requirements"), "no provider satisfies cluster requirements of execution %v", e.Name)
+		}
+	}
+}
+
+// lookupNode - find YAML node by path of mapping keys and sequence indexes,
+// the closest existing parent is returned if path could not be followed completely.
+func lookupNode(node *yamlv3.Node, path ...interface{}) *yamlv3.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yamlv3.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, elem := range path {
+		var next *yamlv3.Node
+		switch key := elem.(type) {
+		case string:
+			if node.Kind == yamlv3.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == key {
+						next = node.Content[i+1]
+						if next.Kind == yamlv3.ScalarNode {
+							// Point to a key of scalar values.
+							next = node.Content[i]
+						}
+						break
+					}
+				}
+			}
+		case int:
+			if node.Kind == yamlv3.SequenceNode && key < len(node.Content) {
+				next = node.Content[key]
+			}
+		}
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+	return node
+}
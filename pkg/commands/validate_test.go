@@ -0,0 +1,148 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const validConfig = `version: 1.0
+root: ./.tests
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+    scripts:
+      config: "echo config"
+      start: "echo start"
+      stop: "echo stop"
+executions:
+  - name: "simple"
+    root: ./tests
+`
+
+const invalidConfig = `version: 1.0
+root: ./.tests
+retest:
+  pattern:
+    - "[unclosed"
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+    scripts:
+      config: "echo config"
+      start: "echo start"
+      stop: "echo stop"
+  - name: "b_provider"
+    kind: "cloud"
+    instances: 1
+executions:
+  - name: "interdomain"
+    cluster-count: 3
+    cluster-selector:
+      - a_provider
+      - c_provider
+  - name: "shell"
+    kind: shell
+    timeuot: 10
+import:
+  - %s
+`
+
+const importedConfig = `executions:
+  - name: "interdomain"
+    cluster-requirements:
+      - key: nodes
+        operator: Gt
+`
+
+const envCheckConfig = `version: 1.0
+root: ./.tests
+providers:
+  - name: "a_provider"
+    kind: "shell"
+    instances: 1
+    enabled: %v
+    env-check:
+      - CLOUDTEST_VALIDATE_MISSING_TOKEN
+    scripts:
+      config: "echo config"
+      start: "echo start"
+      stop: "echo stop"
+executions:
+  - name: "simple"
+    root: ./tests
+`
+
+func TestValidateConfig(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	configFile := filepath.Join(tmpDir, "valid.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(validConfig), 0600))
+	require.Empty(t, validateConfigFile(configFile))
+}
+
+func TestValidateInvalidConfig(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	importFile := filepath.Join(tmpDir, "imported.yaml")
+	require.NoError(t, ioutil.WriteFile(importFile, []byte(importedConfig), 0600))
+	configFile := filepath.Join(tmpDir, "invalid.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(fmt.Sprintf(invalidConfig, importFile)), 0600))
+
+	var problems []string
+	for _, p := range validateConfigFile(configFile) {
+		problems = append(problems, p.String())
+	}
+	require.Equal(t, []string{
+		importFile + ":2:5: execution interdomain is already defined",
+		importFile + ":4:9: invalid cluster requirement of execution interdomain: unknown requirement operator Gt",
+		configFile + ":5:7: invalid retest pattern \"[unclosed\": error parsing regexp: missing closing ]: `[unclosed`",
+		configFile + ":15:5: unknown kind 'cloud' of provider b_provider",
+		configFile + ":19:5: execution interdomain requires 3 clusters, but selects only 2 provider(s)",
+		configFile + ":22:9: execution interdomain selects unknown provider c_provider",
+		configFile + ":23:5: shell execution shell should have 'run' script",
+		configFile + ":25: field timeuot not found in type config.Execution",
+	}, problems)
+}
+
+func TestValidateProviderEnvCheck(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	disabledFile := filepath.Join(tmpDir, "disabled.yaml")
+	require.NoError(t, ioutil.WriteFile(disabledFile, []byte(fmt.Sprintf(envCheckConfig, false)), 0600))
+	require.Empty(t, validateConfigFile(disabledFile))
+
+	enabledFile := filepath.Join(tmpDir, "enabled.yaml")
+	require.NoError(t, ioutil.WriteFile(enabledFile, []byte(fmt.Sprintf(envCheckConfig, true)), 0600))
+	problems := validateConfigFile(enabledFile)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0].String(), "invalid configuration of provider a_provider: environment variable are not specified")
+}
@@ -18,32 +18,38 @@
 package config
 
 type ClusterProviderConfig struct {
-	Name       string            `yaml:"name"`       // name of provider, GKE, Azure, etc.
-	Kind       string            `yaml:"kind"`       // register provider type, 'shell', 'packet'
-	Instances  int               `yaml:"instances"`  // Number of required instances, executions will be split between instances.
-	Timeout    int               `yaml:"timeout"`    // Timeout for start, stop
-	RetryCount int               `yaml:"retry"`      // A count of start retrying steps.
-	NodeCount  int               `yaml:"node-count"` // A count of nodes should be available via API to match cluster is alive.
-	StopDelay  int64             `yaml:"stop-delay"` // A timeout after stop and starting of session again.
-	Enabled    bool              `yaml:"enabled"`    // Is it enabled by default or not
-	Parameters map[string]string `yaml:"parameters"` // A parameters specific for provider
-	Scripts    map[string]string `yaml:"scripts"`    // A parameters specific for provider
-	Env        []string          `yaml:"env"`        // Extra environment variables
-	EnvCheck   []string          `yaml:"env-check"`  // Check if environment has required environment variables present.
-	Packet     *PacketConfig     `yaml:"packet"`     // A Packet provider configuration
-	TestDelay  int               `yaml:"test-delay"` // Delay between tests of this cluster will be executed in second.
+	Name       string            `yaml:"name"`        // name of provider, GKE, Azure, etc.
+	Kind       string            `yaml:"kind"`        // register provider type, 'shell', 'packet'
+	Instances  int               `yaml:"instances"`   // Number of required instances, executions will be split between instances.
+	Timeout    int               `yaml:"timeout"`     // Timeout for start, stop
+	RetryCount int               `yaml:"retry"`       // A count of start retrying steps.
+	NodeCount  int               `yaml:"node-count"`  // A count of nodes should be available via API to match cluster is alive.
+	StopDelay  int64             `yaml:"stop-delay"`  // A timeout after stop and starting of session again.
+	Enabled    bool              `yaml:"enabled"`     // Is it enabled by default or not
+	Parameters map[string]string `yaml:"parameters"`  // A parameters specific for provider
+	Scripts    map[string]string `yaml:"scripts"`     // A parameters specific for provider
+	Env        []string          `yaml:"env"`         // Extra environment variables
+	EnvCheck   []string          `yaml:"env-check"`   // Check if environment has required environment variables present.
+	Packet     *PacketConfig     `yaml:"packet"`      // A Packet provider configuration
+	Existing   *ExistingConfig   `yaml:"existing"`    // An existing clusters provider configuration
+	TestDelay  int               `yaml:"test-delay"`  // Delay between tests of this cluster will be executed in second.
+	WarmSpares int               `yaml:"warm-spares"` // A number of extra instances to keep started in background while there are pending tasks.
+	Labels     map[string]string `yaml:"labels"`      // Labels describing cluster capabilities, like node count, arch or features, matched by execution requirements.
+	Validation *ValidationConfig `yaml:"validation"`  // Additional cluster health checks, by default only number of ready nodes is checked.
 }
 
 type ExecutionSource struct {
-	Tags  []string `yaml:"tags"`  // A list of tags for this configured execution.
-	Tests []string `yaml:"tests"` // A list of tests for execution.
+	Tags     []string `yaml:"tags"`     // A list of tags for this configured execution.
+	Tests    []string `yaml:"tests"`    // A list of tests for execution.
+	Packages []string `yaml:"packages"` // Package patterns relative to root, './...' to find tests recursively, root package by default.
+	Subtests bool     `yaml:"subtests"` // Find table-driven subtests and split them between cluster instances.
 }
 
 type Execution struct {
 	Source          ExecutionSource `yaml:"source"`           // A source for tests execution
 	Before          string          `yaml:"before"`           // A script to execute against required cluster, called before run tasks from execution.
 	After           string          `yaml:"after"`            // A script to execute against required cluster, called when all tasks from execution are done on cluster instance.
-	Kind            string          `yaml:"kind"`             // Execution kind, default is 'gotest', 'shell' could be used for pure shell tests.
+	Kind            string          `yaml:"kind"`             // Execution kind, default is 'gotest', 'shell' could be used for pure shell tests, 'ginkgo' for Ginkgo suites.
 	Name            string          `yaml:"name"`             // Execution name
 	OnlyRun         []string        `yaml:"only-run"`         // If non-empty, only run the listed tests
 	PackageRoot     string          `yaml:"root"`             // A package root for this test execution, default .
@@ -55,11 +61,26 @@ type Execution struct {
 	Env             []string        `yaml:"env"`              // Additional environment variables
 	Run             string          `yaml:"run"`              // A script to execute against required cluster
 	OnFail          string          `yaml:"on-fail"`          // A script to execute against required cluster, called if task failed
+	Image           string          `yaml:"image"`            // A docker image to execute tests inside of, tests are executed on host by default.
+	ImageOptions    []string        `yaml:"image-options"`    // Additional docker run options of image.
+
+	ClusterRequirements []*ClusterRequirement `yaml:"cluster-requirements"` // Requirements to cluster labels, every test is executed once on any matching cluster.
+	Diagnostics         DiagnosticsConfig     `yaml:"diagnostics"`          // Kubernetes diagnostics collected if test is failed or timed out.
 
 	ConcurrencyRetry int64 `yaml:"test-retry-count"` // A count of times, same test will be executed to find concurrency issues
+	RetryOnFailure   int   `yaml:"retry-on-failure"` // A number of times failed or timed out test is retried, preferably on other cluster instance.
 	TestsFound       int   `yaml:"-"`                // Number of tests found for the config
 }
 
+type DiagnosticsConfig struct {
+	Enabled       bool     `yaml:"enabled"`        // Collect diagnostics of every cluster of failed or timed out test.
+	Namespaces    []string `yaml:"namespaces"`     // Namespaces to collect, all namespaces by default.
+	LabelSelector string   `yaml:"label-selector"` // A label selector of pods and workloads to collect.
+	MaxLogSize    int64    `yaml:"max-log-size"`   // A maximum size of every container log in bytes, last bytes are kept, 1MiB by default.
+	MaxTotalSize  int64    `yaml:"max-total-size"` // A maximum size of all container logs of cluster in bytes, 50MiB by default.
+	Timeout       int64    `yaml:"timeout"`        // A timeout of diagnostics collection in seconds, 120 by default.
+}
+
 type RetestConfig struct {
 	// Executions, every execution execute some tests agains configured set of clusters
 	Patterns         []string `yaml:"pattern"`         // Restart test output pattern, to treat as a test restart request, test will be added back for execution.
@@ -75,13 +96,28 @@ type HealthCheckConfig struct {
 	Message  string `yaml:"message"`
 }
 
+type ReporterConfig struct {
+	Format string `yaml:"format"` // A report format, one of junit, tap or test2json.
+	File   string `yaml:"file"`   // A report file location, relative to test root folder.
+}
+
 type CloudTestConfig struct {
 	Version    string                   `yaml:"version"` // Provider file version, 1.0
 	Providers  []*ClusterProviderConfig `yaml:"providers"`
 	ConfigRoot string                   `yaml:"root"` // A provider stored configurations root.
 	Reporting  struct {
 		JUnitReportFile string `yaml:"junit-report"` // A junit report file location, relative to test root folder.
+		HTMLReportFile  string `yaml:"html"`         // A self-contained HTML report file location, relative to test root folder.
+
+		Reporters []*ReporterConfig `yaml:"reporters"` // Additional reports in junit, tap or test2json formats.
 	} `yaml:"reporting"` // A reporting options.
+	Events struct {
+		File   string `yaml:"file"`   // A file to write newline delimited JSON events into.
+		Listen string `yaml:"listen"` // An address to serve events over HTTP, like 'localhost:8090'.
+	} `yaml:"events"` // A live events stream options.
+	Metrics struct {
+		Listen string `yaml:"listen"` // An address to serve Prometheus metrics on, like 'localhost:9090'.
+	} `yaml:"metrics"` // A metrics endpoint options.
 	HealthCheck []*HealthCheckConfig `yaml:"health-check"` // Health checks options.
 	Executions  []*Execution         `yaml:"executions"`
 	Timeout     int64                `yaml:"timeout"` // Global timeout in seconds
@@ -95,10 +131,23 @@ type CloudTestConfig struct {
 	} `yaml:"statistics"` // Statistics options
 
 	ShuffleTests            bool     `yaml:"shuffle-enabled"`    // Shuffle tests before assignment
+	Scheduling              string   `yaml:"scheduling"`         // Tasks scheduling mode, 'fifo' is default, 'longest-first' to start longest tests first.
 	OnlyRun                 []string `yaml:"only-run"`           // If non-empty, only run the listed tests
 	FailedTestsLimit        int      `yaml:"failed-tests-limit"` // If non-zero, terminates testing after failed tests limit is reached
 	MinSuiteSize            int      `yaml:"min-suite-size"`
 	TestsPerClusterInstance int      `yaml:"tests-per-cluster-instance"` // Number of tests per cluster instance
+
+	Timings struct {
+		File            string `yaml:"file"`             // A file with durations of tests, updated after each run.
+		Report          string `yaml:"report"`           // A JUnit report of previous run to take durations of tests from.
+		DefaultDuration int64  `yaml:"default-duration"` // An expected duration of test without history in seconds, average of known durations by default.
+	} `yaml:"timings"` // Historical test timings used by scheduling.
+
+	History struct {
+		File string `yaml:"file"` // A file with outcomes of tests, updated after each run.
+		Size int    `yaml:"size"` // A number of last outcomes to keep for every test, 20 by default.
+	} `yaml:"history"` // Historical test outcomes used to find flaky tests.
+	Quarantine []string `yaml:"quarantine"` // Tests to run, but keep their failures out of totals, a test name or 'execution/test'.
 }
 
 // NewCloudTestConfig - creates a test config with some default values specified.
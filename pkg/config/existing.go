@@ -0,0 +1,35 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+type ExistingClusterConfig struct {
+	KubeConfig string `yaml:"kubeconfig"` // A Kubernetes configuration file location, default is $KUBECONFIG or ~/.kube/config
+	Context    string `yaml:"context"`    // A context to use from configuration file, default is current context
+}
+
+type LeaseConfig struct {
+	Kind      string `yaml:"kind"`      // A lease kind, 'file' (default) to use lock files, 'cluster' to use Lease object inside cluster, 'none' to disable.
+	Root      string `yaml:"root"`      // A folder to put lock files into, default is system temporary folder.
+	Namespace string `yaml:"namespace"` // A namespace of Lease object, default is 'default'.
+	Name      string `yaml:"name"`      // A name of Lease object, default is 'cloudtest-lease'.
+	Timeout   int    `yaml:"timeout"`   // A time in seconds after lease is treated as stale if not renewed, default is 300.
+}
+
+type ExistingConfig struct {
+	Clusters []*ExistingClusterConfig `yaml:"clusters"` // A list of pre-provisioned clusters, every cluster is used by one instance.
+	Lease    LeaseConfig              `yaml:"lease"`    // A lease configuration to take exclusive access to clusters.
+}
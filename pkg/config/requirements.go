@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Cluster requirement operators, semantic is same as for Kubernetes label selectors.
+const (
+	OperatorIn           = "In"
+	OperatorNotIn        = "NotIn"
+	OperatorExists       = "Exists"
+	OperatorDoesNotExist = "DoesNotExist"
+)
+
+// ClusterRequirement - a requirement to cluster provider labels.
+type ClusterRequirement struct {
+	Key      string   `yaml:"key"`      // A label key
+	Operator string   `yaml:"operator"` // An operator, In, NotIn, Exists or DoesNotExist
+	Values   []string `yaml:"values"`   // A list of values for In and NotIn operators
+}
+
+// Validate - check requirement is well formed.
+func (r *ClusterRequirement) Validate() error {
+	if r.Key == "" {
+		return errors.New("requirement key should be specified")
+	}
+	switch r.Operator {
+	case OperatorIn, OperatorNotIn:
+		if len(r.Values) == 0 {
+			return errors.Errorf("requirement %v should have values for operator %v", r.Key, r.Operator)
+		}
+	case OperatorExists, OperatorDoesNotExist:
+		if len(r.Values) > 0 {
+			return errors.Errorf("requirement %v should not have values for operator %v", r.Key, r.Operator)
+		}
+	default:
+		return errors.Errorf("unknown requirement operator %v", r.Operator)
+	}
+	return nil
+}
+
+// Matches - check if labels satisfy requirement.
+func (r *ClusterRequirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case OperatorIn:
+		return ok && containsValue(r.Values, value)
+	case OperatorNotIn:
+		return !ok || !containsValue(r.Values, value)
+	case OperatorExists:
+		return ok
+	case OperatorDoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+func (r *ClusterRequirement) String() string {
+	if len(r.Values) == 0 {
+		return fmt.Sprintf("%s %s", r.Key, r.Operator)
+	}
+	return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ", "))
+}
+
+// MatchesRequirements - check if cluster provider labels satisfy all requirements.
+func (c *ClusterProviderConfig) MatchesRequirements(requirements []*ClusterRequirement) bool {
+	for _, r := range requirements {
+		if !r.Matches(c.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
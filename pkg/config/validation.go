@@ -0,0 +1,39 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+// ValidationCheck - a custom cluster readiness check, either pods selected by label selector should be ready
+// or a script should succeed.
+type ValidationCheck struct {
+	Name      string `yaml:"name"`      // A check name.
+	Namespace string `yaml:"namespace"` // A namespace of pods, 'default' if not specified.
+	Selector  string `yaml:"selector"`  // A label selector of pods, at least one pod should match and all pods should be Running and Ready.
+	Run       string `yaml:"run"`       // A script to execute with KUBECONFIG of cluster, check is passed if script succeed.
+	Timeout   int64  `yaml:"timeout"`   // A script timeout in seconds, 60 by default.
+	Message   string `yaml:"message"`   // A message reported if check is failed.
+}
+
+// ValidationConfig - an additional checks of cluster health, used to decide if cluster is ready and alive.
+type ValidationConfig struct {
+	SystemPods       bool               `yaml:"system-pods"`       // Check pods of system namespaces are Running and Ready.
+	SystemNamespaces []string           `yaml:"system-namespaces"` // A system namespaces, 'kube-system' by default.
+	APILatency       int64              `yaml:"api-latency"`       // A maximum API server response time in milliseconds, not checked if 0.
+	RequestTimeout   int64              `yaml:"request-timeout"`   // A timeout of every other Kubernetes API request in seconds, 30 by default.
+	DaemonSets       []string           `yaml:"daemonsets"`        // DaemonSets should be fully available, 'namespace/name'.
+	Deployments      []string           `yaml:"deployments"`       // Deployments should be fully available, 'namespace/name'.
+	Checks           []*ValidationCheck `yaml:"checks"`            // Custom readiness checks.
+}
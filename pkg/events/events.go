@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events - a stream of test execution events, written as newline delimited JSON to follow a run live.
+package events
+
+import "time"
+
+// SchemaVersion - a version of event schema, it is changed only in case of incompatible changes of event format.
+const SchemaVersion = "1.0"
+
+// Kind - a kind of event.
+type Kind string
+
+// Event kinds.
+const (
+	RunStarted        Kind = "run-started"
+	RunFinished       Kind = "run-finished"
+	RunTerminated     Kind = "run-terminated"
+	ClusterState      Kind = "cluster-state"
+	TaskStarted       Kind = "task-started"
+	TaskFinished      Kind = "task-finished"
+	TaskRetest        Kind = "task-retest"
+	OnFail            Kind = "on-fail"
+	HealthCheckFailed Kind = "health-check-failed"
+)
+
+// Event - an event of test execution.
+type Event struct {
+	Version         string     `json:"version"`                    // A schema version
+	Kind            Kind       `json:"kind"`                       // An event kind
+	Time            time.Time  `json:"time"`                       // A time event is happened
+	TaskID          string     `json:"task-id,omitempty"`          // A task identifier
+	Test            string     `json:"test,omitempty"`             // A test name
+	Execution       string     `json:"execution,omitempty"`        // An execution name
+	ClusterInstance string     `json:"cluster-instance,omitempty"` // A cluster instance identifier, or a set of instances for task events
+	Status          string     `json:"status,omitempty"`           // A task status or cluster instance state name
+	Started         *time.Time `json:"started,omitempty"`          // A task start time
+	Duration        float64    `json:"duration,omitempty"`         // A task duration in seconds
+	Message         string     `json:"message,omitempty"`          // An optional details
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// EventsPath - an HTTP path events stream is served on.
+	EventsPath = "/events"
+
+	subscriberBuffer = 1000
+)
+
+// Stream - write events into a file and serve them over HTTP to connected clients.
+// Clients connected later receive all previous events first, so every client observe a whole run.
+type Stream struct {
+	sync.Mutex
+	file        *os.File
+	server      *http.Server
+	listener    net.Listener
+	history     [][]byte
+	subscribers map[chan []byte]struct{}
+	closed      bool
+}
+
+// NewStream - create events stream, fileName and listen address are optional.
+func NewStream(fileName, listen string) (*Stream, error) {
+	s := &Stream{
+		subscribers: map[chan []byte]struct{}{},
+	}
+	if fileName != "" {
+		file, err := os.OpenFile(filepath.Clean(fileName), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create events file %s", fileName)
+		}
+		s.file = file
+	}
+	if listen != "" {
+		listener, err := net.Listen("tcp", listen)
+		if err != nil {
+			_ = s.Close()
+			return nil, errors.Wrapf(err, "failed to listen events endpoint %s", listen)
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc(EventsPath, s.serveEvents)
+		s.listener = listener
+		s.server = &http.Server{Handler: mux}
+		go func() {
+			if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				logrus.Errorf("Events endpoint is stopped: %v", err)
+			}
+		}()
+		logrus.Infof("Events are served on http://%s%s", listener.Addr(), EventsPath)
+	}
+	return s, nil
+}
+
+// Addr - return an address events are served on, or empty string if HTTP endpoint is disabled.
+func (s *Stream) Addr() string {
+	if s == nil || s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Emit - publish event, it is safe to call on nil stream.
+func (s *Stream) Emit(event *Event) {
+	if s == nil {
+		return
+	}
+	event.Version = SchemaVersion
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		logrus.Errorf("Failed to marshal event: %v", err)
+		return
+	}
+	line = append(line, '\n')
+
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return
+	}
+	if s.file != nil {
+		if _, err := s.file.Write(line); err != nil {
+			logrus.Errorf("Failed to write event: %v", err)
+		}
+	}
+	if s.server == nil {
+		return
+	}
+	s.history = append(s.history, line)
+	for ch := range s.subscribers {
+		select {
+		case ch <- line:
+		default:
+			// Client is too slow, disconnect it, on reconnect it will receive whole history.
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close - close events file and HTTP endpoint.
+func (s *Stream) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.Lock()
+	s.closed = true
+	for ch := range s.subscribers {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+	s.Unlock()
+
+	var result error
+	if s.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.server.Shutdown(ctx); err != nil {
+			result = errors.Wrap(err, "failed to stop events endpoint")
+		}
+	}
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			result = errors.Wrap(err, "failed to close events file")
+		}
+	}
+	return result
+}
+
+func (s *Stream) subscribe() (history [][]byte, ch chan []byte) {
+	s.Lock()
+	defer s.Unlock()
+	history = append(history, s.history...)
+	if s.closed {
+		return history, nil
+	}
+	ch = make(chan []byte, subscriberBuffer)
+	s.subscribers[ch] = struct{}{}
+	return history, ch
+}
+
+func (s *Stream) unsubscribe(ch chan []byte) {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.subscribers[ch]; ok {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (s *Stream) serveEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, _ := w.(http.Flusher)
+	write := func(line []byte) bool {
+		if _, err := w.Write(line); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+
+	history, ch := s.subscribe()
+	for _, line := range history {
+		if !write(line) {
+			s.unsubscribe(ch)
+			return
+		}
+	}
+	if ch == nil {
+		return
+	}
+	defer s.unsubscribe(ch)
+	for {
+		select {
+		case line, ok := <-ch:
+			if !ok || !write(line) {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
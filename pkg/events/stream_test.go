@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readEvents(t *testing.T, scanner *bufio.Scanner, count int) []*Event {
+	var result []*Event
+	for len(result) < count && scanner.Scan() {
+		event := &Event{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), event))
+		result = append(result, event)
+	}
+	require.Len(t, result, count)
+	return result
+}
+
+func TestStreamFile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	fileName := path.Join(tmpDir, "events.ndjson")
+	stream, err := NewStream(fileName, "")
+	require.NoError(t, err)
+	stream.Emit(&Event{Kind: RunStarted})
+	stream.Emit(&Event{Kind: TaskStarted, TaskID: "1", Test: "TestPass"})
+	require.NoError(t, stream.Close())
+	// Events after close are ignored.
+	stream.Emit(&Event{Kind: RunFinished})
+
+	file, err := os.Open(fileName)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	result := readEvents(t, bufio.NewScanner(file), 2)
+	require.Equal(t, RunStarted, result[0].Kind)
+	require.Equal(t, SchemaVersion, result[0].Version)
+	require.False(t, result[0].Time.IsZero())
+	require.Equal(t, TaskStarted, result[1].Kind)
+	require.Equal(t, "TestPass", result[1].Test)
+}
+
+func TestStreamHTTP(t *testing.T) {
+	stream, err := NewStream("", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+
+	stream.Emit(&Event{Kind: RunStarted})
+
+	resp, err := http.Get("http://" + stream.Addr() + EventsPath)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(resp.Body)
+	// A previous event is received first.
+	require.Equal(t, RunStarted, readEvents(t, scanner, 1)[0].Kind)
+
+	stream.Emit(&Event{Kind: ClusterState, ClusterInstance: "a_provider-1", Status: "ready"})
+	event := readEvents(t, scanner, 1)[0]
+	require.Equal(t, ClusterState, event.Kind)
+	require.Equal(t, "a_provider-1", event.ClusterInstance)
+
+	require.NoError(t, stream.Close())
+	require.False(t, scanner.Scan())
+}
+
+func TestNilStream(t *testing.T) {
+	var stream *Stream
+	stream.Emit(&Event{Kind: RunStarted})
+	require.NoError(t, stream.Close())
+	require.Empty(t, stream.Addr())
+}
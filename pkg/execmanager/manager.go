@@ -18,6 +18,7 @@ package execmanager
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -38,15 +39,16 @@ type ExecutionManager interface {
 	OpenFile(category, operationName string) (string, *os.File, error)
 	//GetRoot - associate and get uniq root location based on pattern
 	GetRoot(root string) (string, error)
-	//AddFile - set named file to content.
+	//AddFile - set named file to content, file is replaced atomically.
 	AddFile(fileName string, bytes []byte)
 	//AddFolder creates specific folder
 	AddFolder(category, name string) string
 }
 
 type executionManagerImpl struct {
-	root  string
-	steps map[string]int
+	root    string
+	steps   map[string]int
+	resumed bool
 	sync.Mutex
 }
 
@@ -64,6 +66,8 @@ func (mgr *executionManagerImpl) getCategory(category string) string {
 	val, ok := mgr.steps[category]
 	if ok {
 		val++
+	} else if mgr.resumed {
+		val = mgr.lastCategoryIndex(category) + 1
 	} else {
 		val = 1
 	}
@@ -72,17 +76,9 @@ func (mgr *executionManagerImpl) getCategory(category string) string {
 }
 
 func (mgr *executionManagerImpl) AddFile(fileName string, bytes []byte) {
-	fileName, f, err := utils.OpenFile(mgr.root, fileName)
-
-	if err != nil {
+	if fileName, err := utils.ReplaceFile(mgr.root, fileName, bytes); err != nil {
 		logrus.Errorf("Failed to write file: %s %v", fileName, err)
-		return
-	}
-	_, err = f.Write(bytes)
-	if err != nil {
-		logrus.Errorf("Failed to write content to file, %v", err)
 	}
-	_ = f.Close()
 }
 
 func (mgr *executionManagerImpl) OpenFile(category, operationName string) (string, *os.File, error) {
@@ -128,6 +124,22 @@ func (mgr *executionManagerImpl) GetRoot(root string) (string, error) {
 	}
 }
 
+// lastCategoryIndex - return a maximum index of files stored in category by previous session.
+func (mgr *executionManagerImpl) lastCategoryIndex(category string) int {
+	files, err := ioutil.ReadDir(path.Join(mgr.root, category))
+	if err != nil {
+		return 0
+	}
+	result := 0
+	for _, f := range files {
+		var index int
+		if _, err := fmt.Sscanf(f.Name(), "%03d-", &index); err == nil && index > result {
+			result = index
+		}
+	}
+	return result
+}
+
 //NewExecutionManager - Creates new execution manager based on root dir.
 func NewExecutionManager(root string) ExecutionManager {
 	utils.ClearFolder(root, true)
@@ -136,3 +148,14 @@ func NewExecutionManager(root string) ExecutionManager {
 		steps: map[string]int{},
 	}
 }
+
+// NewResumedExecutionManager - Creates execution manager based on root dir of previous session,
+// files of previous session are kept and numbering of new files is continued.
+func NewResumedExecutionManager(root string) ExecutionManager {
+	utils.CreateFolders(root)
+	return &executionManagerImpl{
+		root:    root,
+		steps:   map[string]int{},
+		resumed: true,
+	}
+}
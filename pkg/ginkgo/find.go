@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginkgo
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+// Find - discover specs of Ginkgo suite in root package, suite is run in dry-run mode and specs are read from JSON report.
+func Find(manager execmanager.ExecutionManager, root string, tags []string) (*model.Suite, error) {
+	reportFile, err := ioutil.TempFile(os.TempDir(), "ginkgo-report-*.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Ginkgo report file")
+	}
+	_ = reportFile.Close()
+	defer func() { _ = os.Remove(reportFile.Name()) }()
+
+	cmd := []string{"go", "test", ".", "-count", "1"}
+	if len(tags) > 0 {
+		cmd = append(cmd, "-tags", strings.Join(tags, ","))
+	}
+	cmd = append(cmd, "-ginkgo.dry-run", "-ginkgo.json-report="+reportFile.Name())
+
+	result, err := utils.ExecRead(context.Background(), root, cmd)
+	manager.AddLog("ginkgo", "find-tests", strings.Join(cmd, " ")+"\n"+strings.Join(result, "\n"))
+	if err != nil {
+		logrus.Errorf("Error getting list of Ginkgo specs: %v\nOutput: %v\nCmdLine: %v", err, result, cmd)
+		return nil, err
+	}
+
+	reports, err := LoadReport(reportFile.Name())
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, errors.Errorf("no Ginkgo suite found in %v", root)
+	}
+	return FindSpecs(reports[0]), nil
+}
+
+// FindSpecs - return a suite with specs which would be run, pending and skipped specs are not included.
+func FindSpecs(report *Report) *model.Suite {
+	suite := &model.Suite{
+		Name: report.SuiteDescription,
+	}
+	seen := map[string]bool{}
+	for _, spec := range report.SpecReports {
+		if !spec.IsSpec() || spec.State == StatePending || spec.State == StateSkipped {
+			continue
+		}
+		name := spec.FullText()
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		suite.Tests = append(suite.Tests, name)
+	}
+	return suite
+}
+
+// FocusPattern - return a Ginkgo focus regular expression matching exactly given specs.
+func FocusPattern(specs []string) string {
+	quoted := make([]string, 0, len(specs))
+	for _, s := range specs {
+		quoted = append(quoted, regexp.QuoteMeta(s))
+	}
+	return "^(" + strings.Join(quoted, "|") + ")$"
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginkgo
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Spec states reported by Ginkgo.
+const (
+	StatePassed      = "passed"
+	StateSkipped     = "skipped"
+	StatePending     = "pending"
+	StateFailed      = "failed"
+	StatePanicked    = "panicked"
+	StateInterrupted = "interrupted"
+	StateAborted     = "aborted"
+	StateTimedout    = "timedout"
+)
+
+// LeafNodeIt - a node type of regular spec.
+const LeafNodeIt = "It"
+
+// Report - a suite report of Ginkgo JSON report file, only fields used by cloudtest are declared.
+type Report struct {
+	SuitePath        string
+	SuiteDescription string
+	SuiteSucceeded   bool
+	StartTime        time.Time
+	EndTime          time.Time
+	SpecReports      []*SpecReport
+}
+
+// SpecReport - a report of one spec or suite level node (BeforeSuite, AfterSuite, etc).
+type SpecReport struct {
+	ContainerHierarchyTexts    []string
+	LeafNodeType               string
+	LeafNodeLocation           CodeLocation
+	LeafNodeText               string
+	State                      string
+	StartTime                  time.Time
+	EndTime                    time.Time
+	RunTime                    time.Duration
+	Failure                    *Failure
+	CapturedGinkgoWriterOutput string
+	CapturedStdOutErr          string
+}
+
+// CodeLocation - a location of node in source code.
+type CodeLocation struct {
+	FileName   string
+	LineNumber int
+}
+
+// Failure - a failure details of spec.
+type Failure struct {
+	Message  string
+	Location CodeLocation
+}
+
+// LoadReport - read Ginkgo JSON report file.
+func LoadReport(fileName string) ([]*Report, error) {
+	content, err := ioutil.ReadFile(filepath.Clean(fileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read Ginkgo report %s", fileName)
+	}
+	var reports []*Report
+	if err = json.Unmarshal(content, &reports); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse Ginkgo report %s", fileName)
+	}
+	return reports, nil
+}
+
+// ReportFile - return a name of Ginkgo JSON report file stored next to test output file.
+func ReportFile(outputFile string) string {
+	return strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + ".json"
+}
+
+// FullText - return a full text of spec, the same text Ginkgo focus filter is matched against.
+func (s *SpecReport) FullText() string {
+	texts := append(append([]string{}, s.ContainerHierarchyTexts...), s.LeafNodeText)
+	var result []string
+	for _, t := range texts {
+		if t != "" {
+			result = append(result, t)
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+// IsSpec - return true if report is about regular spec, not a suite level node.
+func (s *SpecReport) IsSpec() bool {
+	return s.LeafNodeType == LeafNodeIt
+}
+
+// Failed - return true if spec or node is failed.
+func (s *SpecReport) Failed() bool {
+	switch s.State {
+	case StateFailed, StatePanicked, StateInterrupted, StateAborted, StateTimedout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginkgo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/ginkgo"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const (
+	dataplaneSpec = "Heal with [dataplane] restart should restore connection"
+	registrySpec  = "Heal with registry restart should restore connection"
+)
+
+func TestFindSpecs(t *testing.T) {
+	reports, err := ginkgo.LoadReport("./samples/report.json")
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+
+	suite := ginkgo.FindSpecs(reports[0])
+	require.Equal(t, "E2E Suite", suite.Name)
+	require.Equal(t, []string{dataplaneSpec, registrySpec}, suite.Tests)
+}
+
+func TestFocusPattern(t *testing.T) {
+	focus := regexp.MustCompile(ginkgo.FocusPattern([]string{dataplaneSpec}))
+	require.True(t, focus.MatchString(dataplaneSpec))
+	require.False(t, focus.MatchString(registrySpec))
+	require.False(t, focus.MatchString("Heal with d restart should restore connection"))
+}
+
+func TestSplitSpecs(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	outputFile := path.Join(tmpDir, "e2e-run.log")
+	require.NoError(t, ioutil.WriteFile(outputFile, []byte("suite output\n"), os.ModePerm))
+	content, err := ioutil.ReadFile("./samples/report.json")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(ginkgo.ReportFile(outputFile), content, os.ModePerm))
+
+	const notReportedSpec = "Heal with registry restart should reconnect"
+	suite := &model.TestEntry{
+		Name:            "e2e",
+		Kind:            model.GinkgoTestKind,
+		Status:          model.StatusFailed,
+		ExecutionConfig: &config.Execution{Name: "e2e"},
+		Suite: &model.Suite{
+			Name:  "E2E Suite",
+			Tests: []string{dataplaneSpec, registrySpec, notReportedSpec},
+		},
+		Executions: []model.TestEntryExecution{{OutputFile: outputFile, Status: model.StatusFailed}},
+	}
+
+	tests, err := ginkgo.SplitSpecs(suite, execmanager.NewExecutionManager(path.Join(tmpDir, "results")), "a_provider-1")
+	require.NoError(t, err)
+	require.Len(t, tests, 3)
+
+	require.Equal(t, dataplaneSpec, tests[0].Name)
+	require.Equal(t, model.StatusSuccess, tests[0].Status)
+	require.Equal(t, int64(2), int64(tests[0].Duration.Seconds()))
+
+	require.Equal(t, registrySpec, tests[1].Name)
+	require.Equal(t, model.StatusFailed, tests[1].Status)
+	require.Len(t, tests[1].Executions, 1)
+	lines, err := utils.ReadFile(tests[1].Executions[0].OutputFile)
+	require.NoError(t, err)
+	require.Contains(t, lines, "registry restarted")
+	require.Contains(t, lines, "[FAILED] Expected connection to be established")
+
+	require.Equal(t, notReportedSpec, tests[2].Name)
+	require.Equal(t, model.StatusFailed, tests[2].Status)
+	require.Equal(t, suite.Executions, tests[2].Executions)
+
+	suite.Status = model.StatusSuccess
+	tests, err = ginkgo.SplitSpecs(suite, execmanager.NewExecutionManager(path.Join(tmpDir, "results")), "a_provider-1")
+	require.NoError(t, err)
+	require.Equal(t, model.StatusSkipped, tests[2].Status)
+}
+
+func TestSplitSpecsMalformedReport(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	outputFile := path.Join(tmpDir, "e2e-run.log")
+	require.NoError(t, ioutil.WriteFile(outputFile, []byte("suite output\n"), os.ModePerm))
+	content, err := ioutil.ReadFile("./samples/report.json")
+	require.NoError(t, err)
+	// Report is truncated since suite is killed on timeout.
+	require.NoError(t, ioutil.WriteFile(ginkgo.ReportFile(outputFile), content[:len(content)/2], os.ModePerm))
+
+	suite := &model.TestEntry{
+		Name:            "e2e",
+		Kind:            model.GinkgoTestKind,
+		Status:          model.StatusTimeout,
+		ExecutionConfig: &config.Execution{Name: "e2e"},
+		Suite: &model.Suite{
+			Name:  "E2E Suite",
+			Tests: []string{dataplaneSpec, registrySpec},
+		},
+		Executions: []model.TestEntryExecution{{OutputFile: outputFile, Status: model.StatusTimeout}},
+	}
+
+	tests, err := ginkgo.SplitSpecs(suite, execmanager.NewExecutionManager(path.Join(tmpDir, "results")), "a_provider-1")
+	require.NoError(t, err)
+	require.Len(t, tests, 2)
+	for _, test := range tests {
+		require.Equal(t, model.StatusTimeout, test.Status)
+		require.Equal(t, suite.Executions, test.Executions)
+	}
+}
+
+func TestReportFile(t *testing.T) {
+	require.Equal(t, "/tmp/e2e-run.json", ginkgo.ReportFile("/tmp/e2e-run.log"))
+}
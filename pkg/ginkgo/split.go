@@ -0,0 +1,165 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginkgo
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+)
+
+const maxFileNameLength = 100
+
+var fileNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// SplitSpecs returns list of model.TestEntry for the passed/failed Ginkgo suite, spec results are read from JSON reports
+// of all suite executions. Failed suite level nodes (BeforeSuite, AfterSuite, etc.) are returned as separate entries.
+func SplitSpecs(
+	suite *model.TestEntry,
+	manager execmanager.ExecutionManager,
+	clusterTaskID string,
+) (tests []*model.TestEntry, err error) {
+	entries := map[string]*model.TestEntry{}
+	var nodes []*model.TestEntry
+	for _, execution := range suite.Executions {
+		reportFile := ReportFile(execution.OutputFile)
+		if _, err = os.Stat(reportFile); err != nil {
+			// Suite is timed out or failed to compile, no specs are reported.
+			continue
+		}
+		reports, loadErr := LoadReport(reportFile)
+		if loadErr != nil {
+			// Report could be truncated if suite is killed on timeout, specs are treated as not reported.
+			logrus.Warnf("Ginkgo report of %s is ignored: %v", suite.Name, loadErr)
+			continue
+		}
+		for _, report := range reports {
+			for _, spec := range report.SpecReports {
+				name := spec.FullText()
+				if !spec.IsSpec() {
+					if !spec.Failed() {
+						continue
+					}
+					name = spec.LeafNodeType
+				}
+				entry, ok := entries[name]
+				if !ok {
+					entry = &model.TestEntry{
+						Name:            name,
+						ExecutionConfig: suite.ExecutionConfig,
+						Kind:            model.GoTestKind,
+					}
+					entries[name] = entry
+					if !spec.IsSpec() {
+						nodes = append(nodes, entry)
+					}
+				}
+				if err = addSpecExecution(entry, spec, suite.Suite.Name, manager, clusterTaskID); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	tests = append(tests, nodes...)
+	for _, name := range suite.Suite.Tests {
+		entry, ok := entries[name]
+		if !ok {
+			entry = notReportedSpec(name, suite)
+		}
+		tests = append(tests, entry)
+	}
+	return tests, nil
+}
+
+func addSpecExecution(entry *model.TestEntry, spec *SpecReport, suiteName string, manager execmanager.ExecutionManager, clusterTaskID string) error {
+	fileName, file, err := manager.OpenFileTest(clusterTaskID, specFileName(suiteName, entry.Name), "run")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	_, _ = file.WriteString(spec.CapturedStdOutErr)
+	_, _ = file.WriteString(spec.CapturedGinkgoWriterOutput)
+	if spec.Failure != nil && spec.Failure.Message != "" {
+		_, _ = file.WriteString(fmt.Sprintf("\n[%s] %s\n%s:%d\n", strings.ToUpper(spec.State), spec.Failure.Message,
+			spec.Failure.Location.FileName, spec.Failure.Location.LineNumber))
+	}
+
+	status := specStatus(spec)
+	entry.Executions = append(entry.Executions, model.TestEntryExecution{
+		OutputFile: fileName,
+		Retry:      len(entry.Executions) + 1,
+		Status:     status,
+		Started:    spec.StartTime,
+		Duration:   spec.RunTime,
+	})
+	if entry.Started.IsZero() {
+		entry.Started = spec.StartTime
+	}
+	entry.Duration = spec.RunTime
+	entry.Status = status
+	if status == model.StatusSkipped && spec.Failure != nil {
+		entry.SkipMessage = spec.Failure.Message
+	}
+	return nil
+}
+
+func notReportedSpec(name string, suite *model.TestEntry) *model.TestEntry {
+	entry := &model.TestEntry{
+		Name:            name,
+		ExecutionConfig: suite.ExecutionConfig,
+		Started:         suite.Started,
+		Kind:            model.GoTestKind,
+	}
+	switch suite.Status {
+	case model.StatusFailed, model.StatusTimeout:
+		// Suite output is the only information about spec.
+		entry.Status = suite.Status
+		entry.Executions = suite.Executions
+	default:
+		entry.Status = model.StatusSkipped
+		entry.SkipMessage = "Spec was not reported by Ginkgo"
+	}
+	return entry
+}
+
+func specStatus(spec *SpecReport) model.Status {
+	switch {
+	case spec.State == StateTimedout:
+		return model.StatusTimeout
+	case spec.Failed():
+		return model.StatusFailed
+	case spec.State == StateSkipped || spec.State == StatePending:
+		return model.StatusSkipped
+	}
+	return model.StatusSuccess
+}
+
+func specFileName(suiteName, specName string) string {
+	result := strings.Trim(fileNameRegexp.ReplaceAllString(suiteName+"-"+specName, "_"), "_")
+	if len(result) > maxFileNameLength {
+		result = result[:maxFileNameLength]
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const (
+	defaultSystemNamespace = "kube-system"
+	defaultCheckNamespace  = "default"
+	defaultCheckTimeout    = 60
+	defaultRequestTimeout  = 30
+)
+
+// requestTimeout - return a timeout of every Kubernetes API request performed to validate cluster.
+func requestTimeout(cfg *config.ValidationConfig) time.Duration {
+	if cfg != nil && cfg.RequestTimeout > 0 {
+		return time.Duration(cfg.RequestTimeout) * time.Second
+	}
+	return defaultRequestTimeout * time.Second
+}
+
+// validateHealth - perform configured cluster health checks, all failed checks are reported.
+func (v *k8sValidator) validateHealth(ctx context.Context, cfg *config.ValidationConfig) error {
+	var failures []string
+	check := func(err error) {
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if cfg.APILatency > 0 {
+		check(v.checkAPILatency(time.Duration(cfg.APILatency) * time.Millisecond))
+	}
+	if cfg.SystemPods {
+		namespaces := cfg.SystemNamespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{defaultSystemNamespace}
+		}
+		for _, ns := range namespaces {
+			check(v.checkPods(ctx, ns, "", false))
+		}
+	}
+	for _, name := range cfg.DaemonSets {
+		check(v.checkDaemonSet(ctx, name))
+	}
+	for _, name := range cfg.Deployments {
+		check(v.checkDeployment(ctx, name))
+	}
+	for _, c := range cfg.Checks {
+		if err := v.runCheck(ctx, c); err != nil {
+			msg := c.Message
+			if msg == "" {
+				msg = fmt.Sprintf("Cluster check %v is failed", c.Name)
+			}
+			failures = append(failures, fmt.Sprintf("%v: %v", msg, err))
+		}
+	}
+	if len(failures) > 0 {
+		return errors.Errorf("Cluster is not healthy: %v", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func (v *k8sValidator) checkAPILatency(budget time.Duration) error {
+	client, err := v.utils.discovery(budget)
+	if err != nil {
+		return errors.Wrap(err, "failed to create discovery client")
+	}
+	st := time.Now()
+	if _, err = client.ServerVersion(); err != nil {
+		return errors.Wrapf(err, "API server is not responding within %v", budget)
+	}
+	if latency := time.Since(st); latency > budget {
+		return errors.Errorf("API server response time %v exceeds %v", latency.Round(time.Millisecond), budget)
+	}
+	return nil
+}
+
+// checkPods - check all pods in namespace selected by selector are Running and Ready, Succeeded pods are ignored.
+func (v *k8sValidator) checkPods(ctx context.Context, namespace, selector string, required bool) error {
+	requestCtx, cancel := context.WithTimeout(ctx, requestTimeout(v.config.Validation))
+	defer cancel()
+	pods, err := v.utils.clientset.CoreV1().Pods(namespace).List(requestCtx, v12.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list pods in namespace %s", namespace)
+	}
+	if required && len(pods.Items) == 0 {
+		return errors.Errorf("no pods match '%s' in namespace %s", selector, namespace)
+	}
+	var notReady []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == v1.PodSucceeded {
+			continue
+		}
+		if !isPodHealthy(pod) {
+			notReady = append(notReady, fmt.Sprintf("%s(%s)", pod.Name, podStatus(pod)))
+		}
+	}
+	if len(notReady) > 0 {
+		return errors.Errorf("pods are not ready in namespace %s: %v", namespace, strings.Join(notReady, ", "))
+	}
+	return nil
+}
+
+func (v *k8sValidator) checkDaemonSet(ctx context.Context, name string) error {
+	namespace, name := splitName(name)
+	requestCtx, cancel := context.WithTimeout(ctx, requestTimeout(v.config.Validation))
+	defer cancel()
+	ds, err := v.utils.clientset.AppsV1().DaemonSets(namespace).Get(requestCtx, name, v12.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get daemonset %s/%s", namespace, name)
+	}
+	status := &ds.Status
+	if status.DesiredNumberScheduled == 0 || status.NumberAvailable < status.DesiredNumberScheduled {
+		return errors.Errorf("daemonset %s/%s is not available: %d of %d", namespace, name, status.NumberAvailable, status.DesiredNumberScheduled)
+	}
+	return nil
+}
+
+func (v *k8sValidator) checkDeployment(ctx context.Context, name string) error {
+	namespace, name := splitName(name)
+	requestCtx, cancel := context.WithTimeout(ctx, requestTimeout(v.config.Validation))
+	defer cancel()
+	d, err := v.utils.clientset.AppsV1().Deployments(namespace).Get(requestCtx, name, v12.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get deployment %s/%s", namespace, name)
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	if d.Status.AvailableReplicas < replicas {
+		return errors.Errorf("deployment %s/%s is not available: %d of %d", namespace, name, d.Status.AvailableReplicas, replicas)
+	}
+	return nil
+}
+
+func (v *k8sValidator) runCheck(ctx context.Context, c *config.ValidationCheck) error {
+	namespace := c.Namespace
+	if namespace == "" {
+		namespace = defaultCheckNamespace
+	}
+	if c.Selector != "" {
+		if err := v.checkPods(ctx, namespace, c.Selector, true); err != nil {
+			return err
+		}
+	}
+	if c.Run == "" {
+		return nil
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	output := &strings.Builder{}
+	writer := bufio.NewWriter(output)
+	_, err := utils.RunCommand(timeoutCtx, c.Run, "", func(string) {}, writer,
+		[]string{fmt.Sprintf("KUBECONFIG=%s", v.location)}, map[string]string{}, false)
+	if err != nil {
+		_ = writer.Flush()
+		if out := strings.TrimSpace(output.String()); out != "" {
+			return errors.Wrap(err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+// splitName - split 'namespace/name', default namespace is kube-system.
+func splitName(value string) (namespace, name string) {
+	if pos := strings.Index(value, "/"); pos != -1 {
+		return value[:pos], value[pos+1:]
+	}
+	return defaultSystemNamespace, value
+}
@@ -0,0 +1,402 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// DefaultMaxLogSize - a default maximum size of every container log.
+	DefaultMaxLogSize = 1 << 20
+	// DefaultMaxTotalSize - a default maximum size of all collected logs.
+	DefaultMaxTotalSize = 50 << 20
+)
+
+// DiagnosticsOptions - a Kubernetes diagnostics collection options.
+type DiagnosticsOptions struct {
+	Namespaces    []string // Namespaces to collect, all namespaces if empty.
+	LabelSelector string   // A label selector of pods and workloads to collect.
+	MaxLogSize    int64    // A maximum size of every container log, last bytes are kept.
+	MaxTotalSize  int64    // A maximum size of all collected logs, rest of logs are skipped.
+}
+
+// logStreamFunc - open a log stream of pod container.
+type logStreamFunc func(ctx context.Context, pod *v1.Pod, options *v1.PodLogOptions) (io.ReadCloser, error)
+
+type diagnosticsCollector struct {
+	utils      *Utils
+	streamLog  logStreamFunc
+	ctx        context.Context
+	dir        string
+	options    DiagnosticsOptions
+	namespaces []string
+	logsSize   int64
+	skipped    []string
+}
+
+// CollectDiagnostics - store all pods with status, container logs (current and previous), events sorted by time,
+// node conditions and YAML of failing workloads into dir. Collection continues on errors, all of them are returned.
+func (u *Utils) CollectDiagnostics(ctx context.Context, dir string, options *DiagnosticsOptions) error {
+	return u.collectDiagnostics(ctx, dir, options, u.streamLog)
+}
+
+func (u *Utils) streamLog(ctx context.Context, pod *v1.Pod, options *v1.PodLogOptions) (io.ReadCloser, error) {
+	return u.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).Stream(ctx)
+}
+
+func (u *Utils) collectDiagnostics(ctx context.Context, dir string, options *DiagnosticsOptions, streamLog logStreamFunc) error {
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), os.ModePerm); err != nil {
+		return errors.Wrapf(err, "failed to create diagnostics folder %s", dir)
+	}
+	c := &diagnosticsCollector{
+		utils:      u,
+		streamLog:  streamLog,
+		ctx:        ctx,
+		dir:        dir,
+		options:    *options,
+		namespaces: options.Namespaces,
+	}
+	if c.options.MaxLogSize <= 0 {
+		c.options.MaxLogSize = DefaultMaxLogSize
+	}
+	if c.options.MaxTotalSize <= 0 {
+		c.options.MaxTotalSize = DefaultMaxTotalSize
+	}
+	if len(c.namespaces) == 0 {
+		c.namespaces = []string{v12.NamespaceAll}
+	}
+
+	var errs []string
+	for _, collect := range []func() error{c.collectNodes, c.collectPods, c.collectEvents, c.collectWorkloads} {
+		if err := collect(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(c.skipped) > 0 {
+		content := fmt.Sprintf("A total logs size limit %d is reached, skipped logs:\n%s\n", c.options.MaxTotalSize, strings.Join(c.skipped, "\n"))
+		if err := c.writeFile("logs-skipped.txt", []byte(content)); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("failed to collect diagnostics: %v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *diagnosticsCollector) writeFile(name string, content []byte) error {
+	if err := ioutil.WriteFile(filepath.Join(c.dir, name), content, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+	return nil
+}
+
+func (c *diagnosticsCollector) collectNodes() error {
+	nodes, err := c.utils.clientset.CoreV1().Nodes().List(c.ctx, v12.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list nodes")
+	}
+	out := &strings.Builder{}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NODE\tCONDITION\tSTATUS\tREASON\tMESSAGE")
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		for _, cond := range node.Status.Conditions {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", node.Name, cond.Type, cond.Status, cond.Reason, cond.Message)
+		}
+	}
+	_ = w.Flush()
+	return c.writeFile("nodes.txt", []byte(out.String()))
+}
+
+func (c *diagnosticsCollector) collectPods() error {
+	out := &strings.Builder{}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAMESPACE\tNAME\tREADY\tSTATUS\tRESTARTS\tNODE\tAGE")
+	var errs []string
+	for _, ns := range c.namespaces {
+		pods, err := c.utils.clientset.CoreV1().Pods(ns).List(c.ctx, v12.ListOptions{LabelSelector: c.options.LabelSelector})
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to list pods in namespace '%s'", ns).Error())
+			continue
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			ready, restarts := 0, int32(0)
+			for _, cs := range pod.Status.ContainerStatuses {
+				if cs.Ready {
+					ready++
+				}
+				restarts += cs.RestartCount
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%d\t%s\t%v\n", pod.Namespace, pod.Name, ready, len(pod.Spec.Containers),
+				podStatus(pod), restarts, pod.Spec.NodeName, age(pod.CreationTimestamp))
+			for _, cs := range append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+				if err := c.collectLog(pod, cs.Name, false); err != nil {
+					errs = append(errs, err.Error())
+				}
+				if cs.RestartCount > 0 {
+					if err := c.collectLog(pod, cs.Name, true); err != nil {
+						errs = append(errs, err.Error())
+					}
+				}
+			}
+		}
+	}
+	_ = w.Flush()
+	if err := c.writeFile("pods.txt", []byte(out.String())); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *diagnosticsCollector) collectLog(pod *v1.Pod, container string, previous bool) error {
+	name := fmt.Sprintf("%s_%s_%s", pod.Namespace, pod.Name, container)
+	if previous {
+		name += ".previous"
+	}
+	name = filepath.Join("logs", name+".log")
+	if c.logsSize >= c.options.MaxTotalSize {
+		c.skipped = append(c.skipped, name)
+		return nil
+	}
+
+	stream, err := c.streamLog(c.ctx, pod, &v1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get log of %s/%s container %s", pod.Namespace, pod.Name, container)
+	}
+	defer func() { _ = stream.Close() }()
+
+	tail := &tailBuffer{limit: c.options.MaxLogSize}
+	if _, err = io.Copy(tail, stream); err != nil {
+		return errors.Wrapf(err, "failed to read log of %s/%s container %s", pod.Namespace, pod.Name, container)
+	}
+	content := tail.bytes()
+	if remains := c.options.MaxTotalSize - c.logsSize; int64(len(content)) > remains {
+		content = content[int64(len(content))-remains:]
+	}
+	c.logsSize += int64(len(content))
+	return c.writeFile(name, content)
+}
+
+func (c *diagnosticsCollector) collectEvents() error {
+	var items []v1.Event
+	for _, ns := range c.namespaces {
+		list, err := c.utils.clientset.CoreV1().Events(ns).List(c.ctx, v12.ListOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list events in namespace '%s'", ns)
+		}
+		items = append(items, list.Items...)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return eventTime(&items[i]).Before(eventTime(&items[j]))
+	})
+
+	out := &strings.Builder{}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tNAMESPACE\tTYPE\tREASON\tOBJECT\tCOUNT\tMESSAGE")
+	for i := range items {
+		e := &items[i]
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s/%s\t%d\t%s\n", eventTime(e).Format(time.RFC3339), e.Namespace, e.Type, e.Reason,
+			strings.ToLower(e.InvolvedObject.Kind), e.InvolvedObject.Name, e.Count, strings.TrimSpace(e.Message))
+	}
+	_ = w.Flush()
+	return c.writeFile("events.txt", []byte(out.String()))
+}
+
+// collectWorkloads - store YAML of pods, deployments, stateful and daemon sets which are not ready.
+func (c *diagnosticsCollector) collectWorkloads() error {
+	if err := os.MkdirAll(filepath.Join(c.dir, "workloads"), os.ModePerm); err != nil {
+		return errors.Wrap(err, "failed to create workloads folder")
+	}
+	options := v12.ListOptions{LabelSelector: c.options.LabelSelector}
+	apps := c.utils.clientset.AppsV1()
+	var errs []string
+	for _, ns := range c.namespaces {
+		if pods, err := c.utils.clientset.CoreV1().Pods(ns).List(c.ctx, options); err == nil {
+			for i := range pods.Items {
+				if !isPodHealthy(&pods.Items[i]) {
+					errs = c.writeWorkload(errs, "Pod", "v1", &pods.Items[i].ObjectMeta, &pods.Items[i])
+				}
+			}
+		} else {
+			errs = append(errs, errors.Wrapf(err, "failed to list pods in namespace '%s'", ns).Error())
+		}
+		if deployments, err := apps.Deployments(ns).List(c.ctx, options); err == nil {
+			for i := range deployments.Items {
+				if deployments.Items[i].Status.UnavailableReplicas > 0 {
+					errs = c.writeWorkload(errs, "Deployment", "apps/v1", &deployments.Items[i].ObjectMeta, &deployments.Items[i])
+				}
+			}
+		} else {
+			errs = append(errs, errors.Wrapf(err, "failed to list deployments in namespace '%s'", ns).Error())
+		}
+		if sets, err := apps.StatefulSets(ns).List(c.ctx, options); err == nil {
+			for i := range sets.Items {
+				if !isStatefulSetReady(&sets.Items[i]) {
+					errs = c.writeWorkload(errs, "StatefulSet", "apps/v1", &sets.Items[i].ObjectMeta, &sets.Items[i])
+				}
+			}
+		} else {
+			errs = append(errs, errors.Wrapf(err, "failed to list stateful sets in namespace '%s'", ns).Error())
+		}
+		if sets, err := apps.DaemonSets(ns).List(c.ctx, options); err == nil {
+			for i := range sets.Items {
+				if sets.Items[i].Status.NumberUnavailable > 0 {
+					errs = c.writeWorkload(errs, "DaemonSet", "apps/v1", &sets.Items[i].ObjectMeta, &sets.Items[i])
+				}
+			}
+		} else {
+			errs = append(errs, errors.Wrapf(err, "failed to list daemon sets in namespace '%s'", ns).Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *diagnosticsCollector) writeWorkload(errs []string, kind, apiVersion string, meta *v12.ObjectMeta, obj runtime.Object) []string {
+	meta.ManagedFields = nil
+	obj.GetObjectKind().SetGroupVersionKind(schema.FromAPIVersionAndKind(apiVersion, kind))
+	content, err := toYaml(obj)
+	if err == nil {
+		err = c.writeFile(filepath.Join("workloads", fmt.Sprintf("%s_%s_%s.yaml", meta.Namespace, strings.ToLower(kind), meta.Name)), content)
+	}
+	if err != nil {
+		errs = append(errs, errors.Wrapf(err, "failed to store %s %s/%s", kind, meta.Namespace, meta.Name).Error())
+	}
+	return errs
+}
+
+// toYaml - convert Kubernetes object into YAML using its JSON field names.
+func toYaml(obj interface{}) ([]byte, error) {
+	content, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err = json.Unmarshal(content, &value); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(value)
+}
+
+func isPodHealthy(pod *v1.Pod) bool {
+	switch pod.Status.Phase {
+	case v1.PodSucceeded:
+		return true
+	case v1.PodRunning:
+		for _, cs := range pod.Status.ContainerStatuses {
+			if !cs.Ready {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func isStatefulSetReady(set *appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if set.Spec.Replicas != nil {
+		replicas = *set.Spec.Replicas
+	}
+	return set.Status.ReadyReplicas >= replicas
+}
+
+// podStatus - return a pod status like kubectl does, a reason of waiting or terminated container if any.
+func podStatus(pod *v1.Pod) string {
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		switch {
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "":
+			return cs.State.Waiting.Reason
+		case cs.State.Terminated != nil && cs.State.Terminated.Reason != "" && pod.Status.Phase != v1.PodSucceeded:
+			return cs.State.Terminated.Reason
+		}
+	}
+	return status
+}
+
+func eventTime(e *v1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.FirstTimestamp.Time
+}
+
+func age(created v12.Time) time.Duration {
+	if created.IsZero() {
+		return 0
+	}
+	return time.Since(created.Time).Round(time.Second)
+}
+
+// tailBuffer - a writer keeping only last limit bytes.
+type tailBuffer struct {
+	limit     int64
+	data      []byte
+	truncated bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if extra := int64(len(b.data)) - b.limit; extra > 0 {
+		b.data = b.data[extra:]
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) bytes() []byte {
+	if !b.truncated {
+		return b.data
+	}
+	return append([]byte(fmt.Sprintf("... log is truncated to last %d bytes\n", b.limit)), b.data...)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCollectDiagnostics(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "diagnostics")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	replicas := int32(2)
+	u := &Utils{clientset: fake.NewSimpleClientset(
+		&v1.Node{
+			ObjectMeta: v12.ObjectMeta{Name: "node-1"},
+			Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionTrue, Reason: "KubeletReady"},
+			}},
+		},
+		&v1.Pod{
+			ObjectMeta: v12.ObjectMeta{Name: "nse", Namespace: "default"},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "nse"}}},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:         "nse",
+					RestartCount: 3,
+					State:        v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				}},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: v12.ObjectMeta{Name: "nsc", Namespace: "default"},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "nsc"}}},
+			Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{Name: "nsc", Ready: true}},
+			},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: v12.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+		},
+		&v1.Event{
+			ObjectMeta:     v12.ObjectMeta{Name: "second", Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "nse"},
+			Reason:         "BackOff",
+			LastTimestamp:  v12.Unix(200, 0),
+		},
+		&v1.Event{
+			ObjectMeta:     v12.ObjectMeta{Name: "first", Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "nse"},
+			Reason:         "Pulled",
+			LastTimestamp:  v12.Unix(100, 0),
+		},
+	)}
+
+	// Fake clientset could not stream logs, so they are stubbed.
+	streamLog := func(_ context.Context, pod *v1.Pod, options *v1.PodLogOptions) (io.ReadCloser, error) {
+		content := fmt.Sprintf("log of %s/%s previous: %v\n", pod.Name, options.Container, options.Previous)
+		return ioutil.NopCloser(strings.NewReader(content)), nil
+	}
+	err = u.collectDiagnostics(context.Background(), tmpDir, &DiagnosticsOptions{Namespaces: []string{"default"}}, streamLog)
+	require.NoError(t, err)
+
+	read := func(name string) string {
+		content, readErr := ioutil.ReadFile(filepath.Clean(filepath.Join(tmpDir, name)))
+		require.NoError(t, readErr)
+		return string(content)
+	}
+
+	require.Contains(t, read("nodes.txt"), "KubeletReady")
+	pods := read("pods.txt")
+	require.Regexp(t, `default\s+nse\s+0/1\s+CrashLoopBackOff\s+3`, pods)
+	require.Regexp(t, `default\s+nsc\s+1/1\s+Running\s+0`, pods)
+
+	require.Equal(t, "log of nse/nse previous: false\n", read(filepath.Join("logs", "default_nse_nse.log")))
+	require.Equal(t, "log of nse/nse previous: true\n", read(filepath.Join("logs", "default_nse_nse.previous.log")))
+	require.Equal(t, "log of nsc/nsc previous: false\n", read(filepath.Join("logs", "default_nsc_nsc.log")))
+	require.NoFileExists(t, filepath.Join(tmpDir, "logs", "default_nsc_nsc.previous.log"))
+
+	require.Regexp(t, `(?s)Pulled.*BackOff`, read("events.txt"))
+
+	require.Contains(t, read(filepath.Join("workloads", "default_pod_nse.yaml")), "kind: Pod")
+	require.Contains(t, read(filepath.Join("workloads", "default_statefulset_db.yaml")), "readyReplicas: 1")
+	require.NoFileExists(t, filepath.Join(tmpDir, "workloads", "default_pod_nsc.yaml"))
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{limit: 4}
+	_, _ = b.Write([]byte("abc"))
+	require.Equal(t, "abc", string(b.bytes()))
+	_, _ = b.Write([]byte("defg"))
+	require.Equal(t, "... log is truncated to last 4 bytes\ndefg", string(b.bytes()))
+}
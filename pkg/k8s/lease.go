@@ -0,0 +1,136 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrLeaseLost - a lease is expired and taken by another holder.
+var ErrLeaseLost = errors.New("lease is lost")
+
+// AcquireLease - try to take a coordination Lease object for holder.
+// Return false if lease is held by another holder and is not expired yet.
+func (u *Utils) AcquireLease(ctx context.Context, namespace, name, holder string, duration time.Duration) (bool, error) {
+	leases := u.clientset.CoordinationV1().Leases(namespace)
+	now := v12.NewMicroTime(time.Now())
+	durationSeconds := int32(duration.Seconds())
+
+	lease, err := leases.Get(ctx, name, v12.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: v12.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, v12.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// Someone else was faster.
+			return false, nil
+		}
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if isLeaseHeld(lease, holder) {
+		return false, nil
+	}
+
+	lease.Spec.HolderIdentity = &holder
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, v12.UpdateOptions{})
+	if apierrors.IsConflict(err) {
+		// Lease was updated by someone else.
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// RenewLease - update renew time of Lease object held by holder.
+func (u *Utils) RenewLease(ctx context.Context, namespace, name, holder string) error {
+	leases := u.clientset.CoordinationV1().Leases(namespace)
+	lease, err := leases.Get(ctx, name, v12.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		return errors.Wrapf(ErrLeaseLost, "lease %s/%s is held by %v", namespace, name, leaseHolder(lease))
+	}
+	now := v12.NewMicroTime(time.Now())
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, v12.UpdateOptions{})
+	return err
+}
+
+// ReleaseLease - delete Lease object if it is held by holder.
+func (u *Utils) ReleaseLease(ctx context.Context, namespace, name, holder string) error {
+	leases := u.clientset.CoordinationV1().Leases(namespace)
+	lease, err := leases.Get(ctx, name, v12.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		// Lease is expired and taken by someone else.
+		return nil
+	}
+	err = leases.Delete(ctx, name, v12.DeleteOptions{
+		Preconditions: &v12.Preconditions{ResourceVersion: &lease.ResourceVersion},
+	})
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
+
+// isLeaseHeld - check if lease is held by someone else than holder and it is not expired.
+func isLeaseHeld(lease *coordinationv1.Lease, holder string) bool {
+	spec := &lease.Spec
+	if spec.HolderIdentity == nil || *spec.HolderIdentity == "" || *spec.HolderIdentity == holder {
+		return false
+	}
+	if spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	expireTime := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+	return time.Now().Before(expireTime)
+}
+
+func leaseHolder(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
@@ -18,9 +18,12 @@ package k8s
 
 import (
 	"context"
+	"time"
 
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -29,7 +32,7 @@ import (
 // Utils - basic Kubernetes utils.
 type Utils struct {
 	config    *rest.Config
-	clientset *kubernetes.Clientset
+	clientset kubernetes.Interface
 }
 
 // NewK8sUtils - Creates a new k8s utils with config file.
@@ -46,11 +49,39 @@ func NewK8sUtils(configPath string) (*Utils, error) {
 	return utils, err
 }
 
+// discovery - return a discovery client, requests of which are limited by timeout.
+func (u *Utils) discovery(timeout time.Duration) (discovery.DiscoveryInterface, error) {
+	if u.config == nil {
+		return u.clientset.Discovery(), nil
+	}
+	config := rest.CopyConfig(u.config)
+	config.Timeout = timeout
+	return discovery.NewDiscoveryClientForConfig(config)
+}
+
 // GetNodes - return a list of kubernetes nodes.
-func (u *Utils) GetNodes() ([]v1.Node, error) {
-	nodes, err := u.clientset.CoreV1().Nodes().List(context.TODO(), v12.ListOptions{})
+func (u *Utils) GetNodes(ctx context.Context) ([]v1.Node, error) {
+	nodes, err := u.clientset.CoreV1().Nodes().List(ctx, v12.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 	return nodes.Items, nil
 }
+
+// WriteContextConfig - write Kubernetes configuration with contextName selected as current context into target file.
+// If configPath is empty, default configuration locations are used ($KUBECONFIG, ~/.kube/config).
+func WriteContextConfig(configPath, contextName, target string) error {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = configPath
+	config, err := rules.Load()
+	if err != nil {
+		return err
+	}
+	if contextName != "" {
+		if _, ok := config.Contexts[contextName]; !ok {
+			return errors.Errorf("context %s is not found in Kubernetes configuration %s", contextName, configPath)
+		}
+		config.CurrentContext = contextName
+	}
+	return clientcmd.WriteToFile(*config, target)
+}
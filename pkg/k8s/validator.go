@@ -77,7 +77,10 @@ func isNodeReady(node *v1.Node) bool {
 }
 func (v *k8sValidator) Validate() error {
 	requiedNodes := v.config.NodeCount
-	nodes, err := v.utils.GetNodes()
+	// Every API request is limited, so unresponsive cluster doesn't block validation forever.
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout(v.config.Validation))
+	nodes, err := v.utils.GetNodes(ctx)
+	cancel()
 	if err != nil {
 		return err
 	}
@@ -88,12 +91,14 @@ func (v *k8sValidator) Validate() error {
 			ready++
 		}
 	}
-	if ready >= requiedNodes {
-		return nil
+	if ready < requiedNodes {
+		msg := fmt.Sprintf("Cluster doesn't have required number of nodes to be available. Required: %v Available: %v\n", requiedNodes, ready)
+		return errors.Errorf(msg)
 	}
-	msg := fmt.Sprintf("Cluster doesn't have required number of nodes to be available. Required: %v Available: %v\n", requiedNodes, ready)
-	err = errors.Errorf(msg)
-	return err
+	if v.config.Validation != nil {
+		return v.validateHealth(context.Background(), v.config.Validation)
+	}
+	return nil
 }
 
 func (*k8sFactory) CreateValidator(config *config.ClusterProviderConfig, location string) (KubernetesValidator, error) {
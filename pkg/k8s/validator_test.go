@@ -0,0 +1,157 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+)
+
+func createValidator(validation *config.ValidationConfig, objects ...runtime.Object) *k8sValidator {
+	objects = append(objects, &v1.Node{
+		ObjectMeta: v12.ObjectMeta{Name: "node-1"},
+		Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+			{Type: v1.NodeReady, Status: v1.ConditionTrue},
+		}},
+	})
+	return &k8sValidator{
+		config: &config.ClusterProviderConfig{
+			NodeCount:  1,
+			Validation: validation,
+		},
+		utils: &Utils{clientset: fake.NewSimpleClientset(objects...)},
+	}
+}
+
+func createPod(namespace, name string, ready bool) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: v12.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": name}},
+		Status: v1.PodStatus{
+			Phase:             v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{Name: name, Ready: ready}},
+		},
+	}
+}
+
+func TestValidateNodesOnly(t *testing.T) {
+	v := createValidator(nil, createPod("kube-system", "coredns", false))
+	require.NoError(t, v.Validate())
+
+	v.config.NodeCount = 2
+	require.Error(t, v.Validate())
+}
+
+func TestValidateSystemPods(t *testing.T) {
+	v := createValidator(&config.ValidationConfig{SystemPods: true},
+		createPod("kube-system", "coredns", false),
+		createPod("kube-system", "kube-proxy", true),
+		createPod("default", "broken", false))
+	err := v.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pods are not ready in namespace kube-system: coredns(Running)")
+	require.NotContains(t, err.Error(), "kube-proxy")
+	require.NotContains(t, err.Error(), "broken")
+}
+
+func TestValidateWorkloads(t *testing.T) {
+	replicas := int32(2)
+	validation := &config.ValidationConfig{
+		DaemonSets:  []string{"kube-flannel-ds"},
+		Deployments: []string{"nsm-system/nsmgr"},
+	}
+	v := createValidator(validation,
+		&appsv1.DaemonSet{
+			ObjectMeta: v12.ObjectMeta{Name: "kube-flannel-ds", Namespace: "kube-system"},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, NumberAvailable: 1},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: v12.ObjectMeta{Name: "nsmgr", Namespace: "nsm-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 1},
+		})
+	err := v.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "deployment nsm-system/nsmgr is not available: 1 of 2")
+	require.NotContains(t, err.Error(), "daemonset")
+
+	validation.DaemonSets = append(validation.DaemonSets, "kube-system/missing")
+	validation.Deployments = nil
+	err = v.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to get daemonset kube-system/missing")
+}
+
+func TestValidateCustomChecks(t *testing.T) {
+	validation := &config.ValidationConfig{
+		Checks: []*config.ValidationCheck{
+			{Name: "nse", Selector: "app=nse", Message: "NSE is not deployed"},
+			{Name: "script", Run: "true"},
+		},
+	}
+	v := createValidator(validation)
+	err := v.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "NSE is not deployed: no pods match 'app=nse' in namespace default")
+
+	v = createValidator(validation, createPod("default", "nse", true))
+	require.NoError(t, v.Validate())
+
+	validation.Checks[1].Run = "false"
+	err = v.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Cluster check script is failed")
+}
+
+func TestValidateAPILatency(t *testing.T) {
+	v := createValidator(&config.ValidationConfig{APILatency: 50})
+	require.NoError(t, v.Validate())
+
+	// Validation is not blocked by unresponsive API server.
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+	v.utils.config = &rest.Config{Host: server.URL}
+
+	st := time.Now()
+	err := v.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "API server is not responding within 50ms")
+	require.Less(t, int64(time.Since(st)), int64(time.Second))
+}
+
+func TestRequestTimeout(t *testing.T) {
+	require.Equal(t, defaultRequestTimeout*time.Second, requestTimeout(nil))
+	// API latency budget is used only to probe API server.
+	require.Equal(t, defaultRequestTimeout*time.Second, requestTimeout(&config.ValidationConfig{APILatency: 200}))
+	require.Equal(t, 5*time.Second, requestTimeout(&config.ValidationConfig{APILatency: 200, RequestTimeout: 5}))
+}
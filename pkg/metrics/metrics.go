@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics - a minimal set of metric families written in Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Type - a type of metric family.
+type Type string
+
+// Metric family types.
+const (
+	Counter   Type = "counter"
+	Gauge     Type = "gauge"
+	Histogram Type = "histogram"
+)
+
+// DefaultBuckets - default histogram buckets in seconds, suitable for cluster start and test durations.
+var DefaultBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600}
+
+// Label - a metric label.
+type Label struct {
+	Name  string
+	Value string
+}
+
+type sample struct {
+	labels  []Label
+	value   float64
+	buckets []uint64
+	count   uint64
+}
+
+// Family - a metric family with samples for different label values.
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Buckets []float64
+	samples map[string]*sample
+}
+
+// NewFamily - create metric family of given type, buckets are used for histograms only.
+func NewFamily(name, help string, kind Type, buckets ...float64) *Family {
+	if kind == Histogram && len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	return &Family{
+		Name:    name,
+		Help:    help,
+		Type:    kind,
+		Buckets: buckets,
+		samples: map[string]*sample{},
+	}
+}
+
+func (f *Family) sample(labels []Label) *sample {
+	var key strings.Builder
+	for _, l := range labels {
+		key.WriteString(l.Name + "\x00" + l.Value + "\x00")
+	}
+	s, ok := f.samples[key.String()]
+	if !ok {
+		s = &sample{labels: labels, buckets: make([]uint64, len(f.Buckets))}
+		f.samples[key.String()] = s
+	}
+	return s
+}
+
+// Add - add value to counter or gauge sample with given labels.
+func (f *Family) Add(value float64, labels ...Label) {
+	f.sample(labels).value += value
+}
+
+// Observe - add observation to histogram sample with given labels.
+func (f *Family) Observe(value float64, labels ...Label) {
+	s := f.sample(labels)
+	for i, bound := range f.Buckets {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.count++
+	s.value += value
+}
+
+// Write - write metric families in Prometheus text exposition format.
+func Write(w io.Writer, families ...*Family) error {
+	for _, f := range families {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.Name, escapeHelp(f.Help), f.Name, f.Type); err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(f.samples))
+		for key := range f.samples {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := f.writeSample(w, f.samples[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Family) writeSample(w io.Writer, s *sample) error {
+	if f.Type != Histogram {
+		_, err := fmt.Fprintf(w, "%s%s %s\n", f.Name, formatLabels(s.labels), formatValue(s.value))
+		return err
+	}
+	for i, bound := range f.Buckets {
+		labels := append(append([]Label{}, s.labels...), Label{Name: "le", Value: formatValue(bound)})
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", f.Name, formatLabels(labels), s.buckets[i]); err != nil {
+			return err
+		}
+	}
+	labels := append(append([]Label{}, s.labels...), Label{Name: "le", Value: "+Inf"})
+	_, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+		f.Name, formatLabels(labels), s.count,
+		f.Name, formatLabels(s.labels), formatValue(s.value),
+		f.Name, formatLabels(s.labels), s.count)
+	return err
+}
+
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var parts []string
+	for _, l := range labels {
+		parts = append(parts, fmt.Sprintf("%s=\"%s\"", l.Name, escapeLabel(l.Value)))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func escapeHelp(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	counter := NewFamily("test_total", "A test \"counter\".", Counter)
+	counter.Add(1, Label{Name: "status", Value: "success"})
+	counter.Add(2, Label{Name: "status", Value: "success"})
+	counter.Add(1, Label{Name: "status", Value: "fail\"ed"})
+
+	histogram := NewFamily("test_duration_seconds", "A test histogram.", Histogram, 1, 10)
+	histogram.Observe(0.5)
+	histogram.Observe(5)
+	histogram.Observe(50)
+
+	builder := &strings.Builder{}
+	require.NoError(t, Write(builder, counter, histogram))
+	require.Equal(t, `# HELP test_total A test "counter".
+# TYPE test_total counter
+test_total{status="fail\"ed"} 1
+test_total{status="success"} 3
+# HELP test_duration_seconds A test histogram.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="1"} 1
+test_duration_seconds_bucket{le="10"} 2
+test_duration_seconds_bucket{le="+Inf"} 3
+test_duration_seconds_sum 55.5
+test_duration_seconds_count 3
+`, builder.String())
+}
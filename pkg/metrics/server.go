@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Path - an HTTP path metrics are served on.
+const Path = "/metrics"
+
+// Server - an HTTP server exposing metric families, families are collected on every scrape.
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewServer - start serving metrics collected by collect function on listen address.
+func NewServer(listen string, collect func() []*Family) (*Server, error) {
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to listen metrics endpoint %s", listen)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := Write(w, collect()...); err != nil {
+			logrus.Errorf("Failed to write metrics: %v", err)
+		}
+	})
+	s := &Server{
+		server:   &http.Server{Handler: mux},
+		listener: listener,
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logrus.Errorf("Metrics endpoint is stopped: %v", err)
+		}
+	}()
+	logrus.Infof("Metrics are served on http://%s%s", listener.Addr(), Path)
+	return s, nil
+}
+
+// Addr - return an address metrics are served on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close - stop metrics endpoint.
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
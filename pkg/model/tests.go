@@ -19,6 +19,7 @@ package model
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -50,6 +51,8 @@ const (
 	StatusSkippedSinceNoClusters
 	// StatusRerunRequest - a test was requested its re-run
 	StatusRerunRequest
+	// StatusRetryRequest - a test is failed and will be retried, according to execution retry-on-failure.
+	StatusRetryRequest
 )
 
 // TestEntryExecution - represent one test execution.
@@ -57,6 +60,10 @@ type TestEntryExecution struct {
 	OutputFile string // Output file name
 	Retry      int    // Did we retry execution on this cluster.
 	Status     Status // Execution status
+
+	ClusterInstances []string      // Cluster instances execution is performed on
+	Started          time.Time     // A time execution is started
+	Duration         time.Duration // A duration of execution
 }
 
 // TestEntryKind - describes a testing way.
@@ -69,6 +76,10 @@ const (
 	ShellTestKind
 	// SuiteTestKind - go test suites
 	SuiteTestKind
+	// GinkgoTestKind - Ginkgo suite, suite specs are split between cluster instances.
+	GinkgoTestKind
+	// SubtestsTestKind - go test with subtests, subtests are split between cluster instances.
+	SubtestsTestKind
 )
 
 // TestEntry - represent one found test
@@ -76,6 +87,8 @@ type TestEntry struct {
 	Name            string // Test name
 	Tags            string // A list of tags
 	Key             string // Unique key
+	Package         string // A package folder relative to execution root, execution root if empty.
+	SplitFrom       string // A name of test the entry is split from, if test is split between cluster instances.
 	ExecutionConfig *config.Execution
 	Suite           *Suite
 
@@ -92,6 +105,49 @@ type TestEntry struct {
 	ArtifactDirectories []string
 }
 
+// PackageDir - return a folder of test package.
+func (t *TestEntry) PackageDir() string {
+	if t.Package == "" {
+		return t.ExecutionConfig.PackageRoot
+	}
+	return filepath.Join(t.ExecutionConfig.PackageRoot, t.Package)
+}
+
+// ListPackages - return folders of packages with tests matching patterns, folders are relative to root.
+func ListPackages(manager execmanager.ExecutionManager, root string, patterns, tags []string) ([]string, error) {
+	cmd := []string{"go", "list", "-f", "{{if or .TestGoFiles .XTestGoFiles}}{{.Dir}}{{end}}"}
+	if len(tags) > 0 {
+		cmd = append(cmd, "-tags", strings.Join(tags, ","))
+	}
+	cmd = append(cmd, patterns...)
+
+	result, err := utils.ExecRead(context.Background(), root, cmd)
+	manager.AddLog("gotest", "list-packages", strings.Join(cmd, " ")+"\n"+strings.Join(result, "\n"))
+	if err != nil {
+		logrus.Errorf("Error getting list of packages: %v\nOutput: %v\nCmdLine: %v", err, result, cmd)
+		return nil, err
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	var packages []string
+	for _, dir := range result {
+		if dir == "" {
+			continue
+		}
+		rel, err := filepath.Rel(absRoot, dir)
+		if err != nil {
+			return nil, err
+		}
+		if rel == "." {
+			rel = ""
+		}
+		packages = append(packages, rel)
+	}
+	return packages, nil
+}
+
 // GetTestConfiguration - Return list of available tests by calling of gotest --list .* $root -tag "" and parsing of output.
 func GetTestConfiguration(manager execmanager.ExecutionManager, root string, source config.ExecutionSource) (map[string]*TestEntry, error) {
 	allTests, err1 := getTests(manager, root)
@@ -0,0 +1,426 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package existing provides a cluster provider to use long-lived, pre-provisioned clusters
+package existing
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/k8s"
+	"github.com/networkservicemesh/cloudtest/pkg/providers"
+	"github.com/networkservicemesh/cloudtest/pkg/shell"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const (
+	resetScript = "reset"
+
+	kubeConfigFile = "config"
+)
+
+var leasePollInterval = 5 * time.Second
+
+type existingProvider struct {
+	root    string
+	indexes map[string]int
+	sync.Mutex
+}
+
+type existingInstance struct {
+	sync.Mutex
+	manager        execmanager.ExecutionManager
+	root           string
+	id             string
+	cluster        *config.ExistingClusterConfig
+	resetScript    []string
+	factory        k8s.ValidationFactory
+	validator      k8s.KubernetesValidator
+	configLocation string
+	shellInterface shell.Manager
+	config         *config.ClusterProviderConfig
+	params         providers.InstanceOptions
+	lease          clusterLease
+	leased         bool
+	leaseErr       error // A reason lease is lost while cluster is in use.
+	cancelRenew    context.CancelFunc
+	started        bool
+}
+
+func (ei *existingInstance) GetID() string {
+	return ei.id
+}
+
+func (ei *existingInstance) CheckIsAlive() error {
+	ei.Lock()
+	defer ei.Unlock()
+	if ei.leaseErr != nil {
+		return ei.leaseErr
+	}
+	if ei.started {
+		return ei.validator.Validate()
+	}
+	return errors.New("cluster is not running")
+}
+
+func (ei *existingInstance) IsRunning() bool {
+	return ei.started
+}
+
+func (ei *existingInstance) GetClusterConfig() (string, error) {
+	if ei.started {
+		return ei.configLocation, nil
+	}
+	return "", errors.New("cluster is not started yet")
+}
+
+func (ei *existingInstance) GetRoot() string {
+	return ei.root
+}
+
+func (ei *existingInstance) Start(timeout time.Duration) (string, error) {
+	logrus.Infof("Starting cluster %s-%s", ei.config.Name, ei.id)
+
+	context, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	utils.ClearFolder(ei.root, true)
+
+	// Process and prepare environment variables
+	err := ei.shellInterface.ProcessEnvironment(ei.id, ei.config.Name, ei.root, ei.config.Env, nil)
+	if err != nil {
+		return "", err
+	}
+	ei.manager.AddLog(ei.id, "environment", ei.shellInterface.PrintEnv(ei.shellInterface.GetProcessedEnv()))
+
+	if ei.configLocation, err = ei.resolveConfigLocation(); err != nil {
+		logrus.Errorf("Failed to resolve cluster configuration of %s: %v", ei.id, err)
+		return "", err
+	}
+
+	if ei.lease, err = ei.createLease(); err != nil {
+		return "", err
+	}
+	if err = ei.acquireLease(context); err != nil {
+		logrus.Errorf("Failed to acquire lease of cluster %s: %v", ei.id, err)
+		return "", err
+	}
+
+	ei.Lock()
+	ei.validator, err = ei.factory.CreateValidator(ei.config, ei.configLocation)
+	ei.Unlock()
+	if err != nil {
+		logrus.Errorf("Failed to start validator %v", err)
+		ei.releaseLease()
+		return "", err
+	}
+
+	st := time.Now()
+	if err = ei.validator.WaitValid(context); err != nil {
+		logrus.Errorf("Failed to wait for required number of nodes: %v", err)
+		ei.releaseLease()
+		return "", err
+	}
+	logrus.Infof("Waiting for desired number of nodes complete %s-%s %v", ei.config.Name, ei.id, time.Since(st))
+
+	ei.started = true
+
+	return "", nil
+}
+
+// Destroy - reset cluster state with reset script and release cluster lease, cluster itself is kept alive.
+func (ei *existingInstance) Destroy(timeout time.Duration) error {
+	logrus.Infof("Destroying cluster  %s", ei.id)
+
+	defer func() {
+		ei.Lock()
+		ei.started = false
+		ei.Unlock()
+	}()
+	defer ei.releaseLease()
+
+	if !ei.leased {
+		// Cluster is used by someone else.
+		return nil
+	}
+	if ei.params.NoStop || len(ei.resetScript) == 0 {
+		return nil
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if _, err := ei.shellInterface.RunCmd(context, "reset", ei.resetScript, []string{"KUBECONFIG=" + ei.configLocation}); err != nil {
+		logrus.Errorf("Failed to reset cluster %s: %v", ei.id, err)
+		return err
+	}
+	return nil
+}
+
+// resolveConfigLocation - return a Kubernetes configuration file to use, selected context is written into instance root.
+func (ei *existingInstance) resolveConfigLocation() (string, error) {
+	kubeConfig := os.ExpandEnv(ei.cluster.KubeConfig)
+	if kubeConfig != "" && ei.cluster.Context == "" {
+		return kubeConfig, nil
+	}
+	configLocation := path.Join(ei.root, kubeConfigFile)
+	if err := k8s.WriteContextConfig(kubeConfig, ei.cluster.Context, configLocation); err != nil {
+		return "", err
+	}
+	return configLocation, nil
+}
+
+func (ei *existingInstance) createLease() (clusterLease, error) {
+	leaseConfig := &ei.config.Existing.Lease
+	leaseTimeout := time.Duration(leaseConfig.Timeout) * time.Second
+	if leaseConfig.Timeout == 0 {
+		leaseTimeout = defaultLeaseTimeout * time.Second
+	}
+
+	hostName, _ := os.Hostname()
+	holder := fmt.Sprintf("%s-%d-%s", hostName, os.Getpid(), ei.id)
+
+	switch leaseConfig.Kind {
+	case noneLeaseKind:
+		return noneLease{}, nil
+	case clusterLeaseKind:
+		return &objectLease{
+			configLocation: ei.configLocation,
+			namespace:      valueOrDefault(leaseConfig.Namespace, defaultLeaseNamespace),
+			name:           valueOrDefault(leaseConfig.Name, defaultLeaseName),
+			holder:         holder,
+			timeout:        leaseTimeout,
+		}, nil
+	case "", fileLeaseKind:
+		key, err := ei.clusterKey()
+		if err != nil {
+			return nil, err
+		}
+		return &fileLease{
+			fileName: path.Join(valueOrDefault(leaseConfig.Root, os.TempDir()), fmt.Sprintf("cloudtest-%x.lock", sha256.Sum256([]byte(key)))),
+			holder:   holder,
+			timeout:  leaseTimeout,
+		}, nil
+	}
+	return nil, errors.Errorf("unsupported lease kind %s", leaseConfig.Kind)
+}
+
+// clusterKey - return an unique key of cluster, same for all processes running on the host.
+func (ei *existingInstance) clusterKey() (string, error) {
+	kubeConfig := os.ExpandEnv(ei.cluster.KubeConfig)
+	if kubeConfig == "" {
+		kubeConfig = os.Getenv("KUBECONFIG")
+	}
+	if kubeConfig != "" {
+		var err error
+		if kubeConfig, err = filepath.Abs(kubeConfig); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s#%s", kubeConfig, ei.cluster.Context), nil
+}
+
+// acquireLease - wait until lease is taken and start its periodic renewal.
+func (ei *existingInstance) acquireLease(ctx context.Context) error {
+	for {
+		acquired, err := ei.lease.tryAcquire(ctx)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			break
+		}
+		logrus.Infof("Cluster %s is leased by someone else, waiting for %v", ei.id, ei.lease)
+		select {
+		case <-time.After(leasePollInterval):
+		case <-ctx.Done():
+			return errors.Errorf("timeout waiting for %v", ei.lease)
+		}
+	}
+	logrus.Infof("Cluster %s lease is acquired: %v", ei.id, ei.lease)
+
+	renewCtx, cancelRenew := context.WithCancel(context.Background())
+	ei.Lock()
+	ei.leased = true
+	ei.leaseErr = nil
+	ei.cancelRenew = cancelRenew
+	ei.Unlock()
+
+	go ei.renewLease(renewCtx, cancelRenew)
+	return nil
+}
+
+// renewLease - periodically renew lease until ctx is done. Lease is lost if it is taken by someone else or is not
+// renewed within lease timeout, cluster is not alive after that.
+func (ei *existingInstance) renewLease(ctx context.Context, cancel context.CancelFunc) {
+	leaseTimeout := time.Duration(ei.config.Existing.Lease.Timeout) * time.Second
+	if leaseTimeout == 0 {
+		leaseTimeout = defaultLeaseTimeout * time.Second
+	}
+	renewed := time.Now()
+	for {
+		select {
+		case <-time.After(leaseTimeout / 3):
+			err := ei.lease.renew(ctx)
+			if err == nil {
+				renewed = time.Now()
+				continue
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Cause(err) != k8s.ErrLeaseLost && time.Since(renewed) < leaseTimeout {
+				logrus.Warnf("Failed to renew lease of cluster %s: %v", ei.id, err)
+				continue
+			}
+			logrus.Errorf("Lease of cluster %s is lost: %v", ei.id, err)
+			ei.Lock()
+			if ctx.Err() == nil {
+				// Lease is not released concurrently.
+				ei.leased = false
+				ei.leaseErr = errors.Wrapf(err, "lease of cluster %s is lost", ei.id)
+			}
+			ei.Unlock()
+			cancel()
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (ei *existingInstance) releaseLease() {
+	ei.Lock()
+	defer ei.Unlock()
+	if !ei.leased {
+		return
+	}
+	ei.cancelRenew()
+	ei.leased = false
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := ei.lease.release(ctx); err != nil {
+		logrus.Errorf("Failed to release lease of cluster %s: %v", ei.id, err)
+		return
+	}
+	logrus.Infof("Cluster %s lease is released: %v", ei.id, ei.lease)
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func (p *existingProvider) getProviderID(provider string) int {
+	val, ok := p.indexes[provider]
+	if ok {
+		val++
+	} else {
+		val = 1
+	}
+	p.indexes[provider] = val
+	return val
+}
+
+func (p *existingProvider) CreateCluster(config *config.ClusterProviderConfig, factory k8s.ValidationFactory,
+	manager execmanager.ExecutionManager,
+	instanceOptions providers.InstanceOptions) (providers.ClusterInstance, error) {
+	err := p.ValidateConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	p.Lock()
+	defer p.Unlock()
+	index := p.getProviderID(config.Name)
+	if index > len(config.Existing.Clusters) {
+		return nil, errors.Errorf("all %d existing clusters of %s are already in use", len(config.Existing.Clusters), config.Name)
+	}
+	id := fmt.Sprintf("%s-%d", config.Name, index)
+
+	clusterInstance := &existingInstance{
+		manager:        manager,
+		root:           path.Join(p.root, id),
+		id:             id,
+		cluster:        config.Existing.Clusters[index-1],
+		resetScript:    utils.ParseScript(config.Scripts[resetScript]),
+		config:         config,
+		factory:        factory,
+		shellInterface: shell.NewManager(manager, id, config, instanceOptions),
+		params:         instanceOptions,
+	}
+
+	return clusterInstance, nil
+}
+
+// CleanupClusters - existing clusters are never leaked, nothing to clean up.
+func (p *existingProvider) CleanupClusters(ctx context.Context, config *config.ClusterProviderConfig,
+	manager execmanager.ExecutionManager, instanceOptions providers.InstanceOptions) {
+}
+
+// NewExistingClusterProvider - Creates new existing clusters provider
+func NewExistingClusterProvider(root string) providers.ClusterProvider {
+	utils.ClearFolder(root, true)
+	return &existingProvider{
+		root:    root,
+		indexes: map[string]int{},
+	}
+}
+
+func (p *existingProvider) ValidateConfig(config *config.ClusterProviderConfig) error {
+	if config.Existing == nil || len(config.Existing.Clusters) == 0 {
+		return errors.New("existing clusters are not specified")
+	}
+	if config.Instances > len(config.Existing.Clusters) {
+		return errors.Errorf("number of instances %d exceeds number of existing clusters %d", config.Instances, len(config.Existing.Clusters))
+	}
+	for i, cluster := range config.Existing.Clusters {
+		if cluster == nil || (cluster.KubeConfig == "" && cluster.Context == "") {
+			return errors.Errorf("existing cluster #%d should have kubeconfig or context specified", i+1)
+		}
+	}
+	switch config.Existing.Lease.Kind {
+	case "", fileLeaseKind, clusterLeaseKind, noneLeaseKind:
+	default:
+		return errors.Errorf("unsupported lease kind %s", config.Existing.Lease.Kind)
+	}
+	if config.Existing.Lease.Timeout < 0 {
+		return errors.Errorf("invalid lease timeout %d", config.Existing.Lease.Timeout)
+	}
+
+	for _, envVar := range config.EnvCheck {
+		envValue := os.Getenv(envVar)
+		if envValue == "" {
+			return errors.Errorf("environment variable are not specified %s Required variables: %v", envValue, config.EnvCheck)
+		}
+	}
+
+	return nil
+}
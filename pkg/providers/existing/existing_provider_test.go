@@ -0,0 +1,286 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package existing
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/providers"
+	"github.com/networkservicemesh/cloudtest/pkg/tests"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: first
+  cluster:
+    server: https://first.example.com
+- name: second
+  cluster:
+    server: https://second.example.com
+contexts:
+- name: first
+  context:
+    cluster: first
+    user: user
+- name: second
+  context:
+    cluster: second
+    user: user
+current-context: first
+users:
+- name: user
+  user:
+    token: token
+`
+
+func createTestConfig(tmpDir string, clusters ...*config.ExistingClusterConfig) *config.ClusterProviderConfig {
+	return &config.ClusterProviderConfig{
+		Name:      "existing",
+		Kind:      "existing",
+		Instances: len(clusters),
+		Scripts: map[string]string{
+			"reset": "echo reset $(cluster-name)",
+		},
+		Existing: &config.ExistingConfig{
+			Clusters: clusters,
+			Lease: config.LeaseConfig{
+				Root:    path.Join(tmpDir, "leases"),
+				Timeout: 60,
+			},
+		},
+	}
+}
+
+func setupTest(t *testing.T) string {
+	leasePollInterval = 10 * time.Millisecond
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(path.Join(tmpDir, "leases"), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(path.Join(tmpDir, "kubeconfig"), []byte(testKubeConfig), 0600))
+	return tmpDir
+}
+
+func leaseFiles(t *testing.T, tmpDir string) []string {
+	files, err := filepath.Glob(path.Join(tmpDir, "leases", "*.lock"))
+	require.NoError(t, err)
+	return files
+}
+
+func TestExistingProviderLease(t *testing.T) {
+	tmpDir := setupTest(t)
+	defer utils.ClearFolder(tmpDir, false)
+
+	cfg := createTestConfig(tmpDir, &config.ExistingClusterConfig{KubeConfig: path.Join(tmpDir, "kubeconfig")})
+	manager := execmanager.NewExecutionManager(path.Join(tmpDir, "results"))
+
+	// Two providers are used to emulate two cloudtest processes.
+	first, err := NewExistingClusterProvider(path.Join(tmpDir, "first")).
+		CreateCluster(cfg, &tests.TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.NoError(t, err)
+	second, err := NewExistingClusterProvider(path.Join(tmpDir, "second")).
+		CreateCluster(cfg, &tests.TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.NoError(t, err)
+
+	_, err = first.Start(time.Minute)
+	require.NoError(t, err)
+	require.True(t, first.IsRunning())
+	require.Len(t, leaseFiles(t, tmpDir), 1)
+
+	kubeConfig, err := first.GetClusterConfig()
+	require.NoError(t, err)
+	require.Equal(t, path.Join(tmpDir, "kubeconfig"), kubeConfig)
+
+	_, err = second.Start(100 * time.Millisecond)
+	require.Error(t, err)
+	require.False(t, second.IsRunning())
+	require.NoError(t, second.Destroy(time.Minute))
+	require.Len(t, leaseFiles(t, tmpDir), 1)
+
+	require.NoError(t, first.Destroy(time.Minute))
+	require.False(t, first.IsRunning())
+	require.Empty(t, leaseFiles(t, tmpDir))
+
+	resetLogs, err := utils.FilterByPattern(utils.GetAllFiles(path.Join(tmpDir, "results", first.GetID())), ".*-reset.log")
+	require.NoError(t, err)
+	require.Len(t, resetLogs, 1)
+
+	_, err = second.Start(time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, second.Destroy(time.Minute))
+}
+
+func TestExistingProviderStaleLease(t *testing.T) {
+	tmpDir := setupTest(t)
+	defer utils.ClearFolder(tmpDir, false)
+
+	cfg := createTestConfig(tmpDir, &config.ExistingClusterConfig{KubeConfig: path.Join(tmpDir, "kubeconfig")})
+	manager := execmanager.NewExecutionManager(path.Join(tmpDir, "results"))
+
+	instance, err := NewExistingClusterProvider(path.Join(tmpDir, "existing")).
+		CreateCluster(cfg, &tests.TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.NoError(t, err)
+	ei := instance.(*existingInstance)
+
+	lease, err := ei.createLease()
+	require.NoError(t, err)
+	staleFile := lease.(*fileLease).fileName
+	require.NoError(t, ioutil.WriteFile(staleFile, []byte("crashed-process"), 0600))
+	staleTime := time.Now().Add(-2 * time.Minute)
+	require.NoError(t, os.Chtimes(staleFile, staleTime, staleTime))
+
+	_, err = instance.Start(time.Minute)
+	require.NoError(t, err)
+
+	holder, err := ioutil.ReadFile(filepath.Clean(staleFile))
+	require.NoError(t, err)
+	require.NotEqual(t, "crashed-process", string(holder))
+
+	require.NoError(t, instance.Destroy(time.Minute))
+	require.Empty(t, leaseFiles(t, tmpDir))
+}
+
+func TestExistingProviderLostLease(t *testing.T) {
+	tmpDir := setupTest(t)
+	defer utils.ClearFolder(tmpDir, false)
+
+	cfg := createTestConfig(tmpDir, &config.ExistingClusterConfig{KubeConfig: path.Join(tmpDir, "kubeconfig")})
+	cfg.Existing.Lease.Timeout = 1
+	manager := execmanager.NewExecutionManager(path.Join(tmpDir, "results"))
+
+	instance, err := NewExistingClusterProvider(path.Join(tmpDir, "existing")).
+		CreateCluster(cfg, &tests.TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.NoError(t, err)
+
+	_, err = instance.Start(time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, instance.CheckIsAlive())
+
+	// Lease is expired and taken by another process.
+	files := leaseFiles(t, tmpDir)
+	require.Len(t, files, 1)
+	require.NoError(t, ioutil.WriteFile(files[0], []byte("another-process"), 0600))
+
+	require.Eventually(t, func() bool {
+		return instance.CheckIsAlive() != nil
+	}, 5*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, instance.Destroy(time.Minute))
+	holder, err := ioutil.ReadFile(filepath.Clean(files[0]))
+	require.NoError(t, err)
+	require.Equal(t, "another-process", string(holder))
+
+	resetLogs, err := utils.FilterByPattern(utils.GetAllFiles(path.Join(tmpDir, "results", instance.GetID())), ".*-reset.log")
+	require.NoError(t, err)
+	require.Empty(t, resetLogs)
+}
+
+func TestFileLeaseKeepsRenewedLock(t *testing.T) {
+	tmpDir := setupTest(t)
+	defer utils.ClearFolder(tmpDir, false)
+
+	lease := &fileLease{
+		fileName: path.Join(tmpDir, "leases", "cluster.lock"),
+		holder:   "first",
+		timeout:  time.Minute,
+	}
+	require.NoError(t, ioutil.WriteFile(lease.fileName, []byte("second"), 0600))
+
+	acquired, err := lease.tryAcquire(context.Background())
+	require.NoError(t, err)
+	require.False(t, acquired)
+	require.Equal(t, "second", readLockHolder(lease.fileName))
+
+	staleTime := time.Now().Add(-2 * time.Minute)
+	require.NoError(t, os.Chtimes(lease.fileName, staleTime, staleTime))
+	acquired, err = lease.tryAcquire(context.Background())
+	require.NoError(t, err)
+	require.False(t, acquired)
+	require.Empty(t, leaseFiles(t, tmpDir))
+	files, err := filepath.Glob(path.Join(tmpDir, "leases", "*"))
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	acquired, err = lease.tryAcquire(context.Background())
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.Equal(t, "first", readLockHolder(lease.fileName))
+}
+
+func TestExistingProviderContext(t *testing.T) {
+	tmpDir := setupTest(t)
+	defer utils.ClearFolder(tmpDir, false)
+
+	cfg := createTestConfig(tmpDir,
+		&config.ExistingClusterConfig{KubeConfig: path.Join(tmpDir, "kubeconfig"), Context: "first"},
+		&config.ExistingClusterConfig{KubeConfig: path.Join(tmpDir, "kubeconfig"), Context: "second"},
+	)
+	cfg.Existing.Lease.Kind = "none"
+	manager := execmanager.NewExecutionManager(path.Join(tmpDir, "results"))
+	provider := NewExistingClusterProvider(path.Join(tmpDir, "existing"))
+
+	for _, contextName := range []string{"first", "second"} {
+		instance, err := provider.CreateCluster(cfg, &tests.TestValidationFactory{}, manager, providers.InstanceOptions{})
+		require.NoError(t, err)
+
+		_, err = instance.Start(time.Minute)
+		require.NoError(t, err)
+
+		kubeConfig, err := instance.GetClusterConfig()
+		require.NoError(t, err)
+		require.Equal(t, path.Join(instance.GetRoot(), kubeConfigFile), kubeConfig)
+
+		loaded, err := clientcmd.LoadFromFile(kubeConfig)
+		require.NoError(t, err)
+		require.Equal(t, contextName, loaded.CurrentContext)
+	}
+
+	_, err := provider.CreateCluster(cfg, &tests.TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.Error(t, err)
+}
+
+func TestExistingProviderValidateConfig(t *testing.T) {
+	provider := NewExistingClusterProvider(path.Join(os.TempDir(), t.Name()))
+
+	require.Error(t, provider.ValidateConfig(&config.ClusterProviderConfig{}))
+
+	cfg := createTestConfig(os.TempDir(), &config.ExistingClusterConfig{Context: "kind-kind"})
+	require.NoError(t, provider.ValidateConfig(cfg))
+
+	cfg.Instances = 2
+	require.Error(t, provider.ValidateConfig(cfg))
+
+	cfg = createTestConfig(os.TempDir(), &config.ExistingClusterConfig{})
+	require.Error(t, provider.ValidateConfig(cfg))
+
+	cfg = createTestConfig(os.TempDir(), &config.ExistingClusterConfig{Context: "kind-kind"})
+	cfg.Existing.Lease.Kind = "redis"
+	require.Error(t, provider.ValidateConfig(cfg))
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package existing
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/k8s"
+)
+
+const (
+	fileLeaseKind    = "file"
+	clusterLeaseKind = "cluster"
+	noneLeaseKind    = "none"
+
+	defaultLeaseNamespace = "default"
+	defaultLeaseName      = "cloudtest-lease"
+	defaultLeaseTimeout   = 300
+)
+
+// clusterLease - an exclusive lease of cluster, expired if it is not renewed in time.
+type clusterLease interface {
+	// tryAcquire - take lease, return false if it is held by someone else right now.
+	tryAcquire(ctx context.Context) (bool, error)
+	// renew - prolong lease held.
+	renew(ctx context.Context) error
+	// release - release lease if it is still held.
+	release(ctx context.Context) error
+	// String - a lease description for logging.
+	String() string
+}
+
+// noneLease - a lease what is always available.
+type noneLease struct{}
+
+func (noneLease) tryAcquire(context.Context) (bool, error) { return true, nil }
+func (noneLease) renew(context.Context) error              { return nil }
+func (noneLease) release(context.Context) error            { return nil }
+func (noneLease) String() string                           { return "none" }
+
+// fileLease - a lease based on lock file, lock file modification time is updated on renew.
+type fileLease struct {
+	fileName string
+	holder   string
+	timeout  time.Duration
+}
+
+func (l *fileLease) tryAcquire(_ context.Context) (bool, error) {
+	file, err := os.OpenFile(l.fileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err == nil {
+		_, err = file.WriteString(l.holder)
+		_ = file.Close()
+		return err == nil, err
+	}
+	if !os.IsExist(err) {
+		return false, err
+	}
+
+	holder := readLockHolder(l.fileName)
+	info, err := os.Stat(l.fileName)
+	if os.IsNotExist(err) {
+		// Released right now, will try next time.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if time.Since(info.ModTime()) < l.timeout {
+		return false, nil
+	}
+
+	// Stale lock file is moved aside atomically and checked again, so a lock renewed or created by someone else
+	// after the check above is not removed.
+	staleName := fmt.Sprintf("%s.%s-%d.stale", l.fileName, l.holder, time.Now().UnixNano())
+	if err = os.Rename(l.fileName, staleName); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer func() { _ = os.Remove(staleName) }()
+
+	info, err = os.Stat(staleName)
+	if err != nil {
+		return false, err
+	}
+	if time.Since(info.ModTime()) < l.timeout || readLockHolder(staleName) != holder {
+		// Lock is taken again, put it back unless someone has already created a new one.
+		if err = os.Link(staleName, l.fileName); err != nil && !os.IsExist(err) {
+			return false, err
+		}
+		return false, nil
+	}
+
+	logrus.Warnf("Lease %s held by %s is stale since %v, expiring it", l.fileName, holder, info.ModTime())
+	return false, nil
+}
+
+func (l *fileLease) renew(context.Context) error {
+	if holder := readLockHolder(l.fileName); holder != l.holder {
+		return errors.Wrapf(k8s.ErrLeaseLost, "lease %s is held by %v", l.fileName, holder)
+	}
+	now := time.Now()
+	return os.Chtimes(l.fileName, now, now)
+}
+
+func (l *fileLease) release(context.Context) error {
+	if readLockHolder(l.fileName) != l.holder {
+		// Lease is expired and taken by someone else.
+		return nil
+	}
+	if err := os.Remove(l.fileName); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func readLockHolder(fileName string) string {
+	content, err := ioutil.ReadFile(filepath.Clean(fileName))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func (l *fileLease) String() string {
+	return fmt.Sprintf("file %s", l.fileName)
+}
+
+// objectLease - a lease based on coordination Lease object inside of cluster.
+type objectLease struct {
+	configLocation string
+	namespace      string
+	name           string
+	holder         string
+	timeout        time.Duration
+	utils          *k8s.Utils
+}
+
+func (l *objectLease) getUtils() (*k8s.Utils, error) {
+	if l.utils == nil {
+		utils, err := k8s.NewK8sUtils(l.configLocation)
+		if err != nil {
+			return nil, err
+		}
+		l.utils = utils
+	}
+	return l.utils, nil
+}
+
+func (l *objectLease) tryAcquire(ctx context.Context) (bool, error) {
+	utils, err := l.getUtils()
+	if err != nil {
+		return false, err
+	}
+	return utils.AcquireLease(ctx, l.namespace, l.name, l.holder, l.timeout)
+}
+
+func (l *objectLease) renew(ctx context.Context) error {
+	utils, err := l.getUtils()
+	if err != nil {
+		return err
+	}
+	return utils.RenewLease(ctx, l.namespace, l.name, l.holder)
+}
+
+func (l *objectLease) release(ctx context.Context) error {
+	utils, err := l.getUtils()
+	if err != nil {
+		return err
+	}
+	return utils.ReleaseLease(ctx, l.namespace, l.name, l.holder)
+}
+
+func (l *objectLease) String() string {
+	return fmt.Sprintf("lease %s/%s", l.namespace, l.name)
+}
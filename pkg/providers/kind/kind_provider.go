@@ -0,0 +1,394 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kind provides a cluster provider to manage kind (Kubernetes IN Docker) clusters
+package kind
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/k8s"
+	"github.com/networkservicemesh/cloudtest/pkg/providers"
+	"github.com/networkservicemesh/cloudtest/pkg/shell"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const (
+	prepareScript = "prepare"
+
+	kindParameter          = "kind"                // A kind binary location, default 'kind'
+	prefixParameter        = "prefix"              // A prefix of created cluster names, default 'cloudtest'
+	configParameter        = "config"              // An inline kind cluster configuration
+	controlPlanesParameter = "control-plane-nodes" // A number of control plane nodes, default 1
+	workersParameter       = "worker-nodes"        // A number of worker nodes, default 0
+	nodeImageParameter     = "node-image"          // A node image to use for cluster nodes
+	imagesParameter        = "images"              // A list of docker images to load into cluster
+	cleanupParameter       = "cleanup-leaked"      // Delete leaked clusters with matching prefix on start, default false
+
+	defaultKind    = "kind"
+	defaultPrefix  = "cloudtest"
+	kindConfigFile = "kind.yaml"
+	kubeConfigFile = "config"
+)
+
+var clusterNamePattern = regexp.MustCompile("^[a-z0-9][a-z0-9.-]*$")
+
+type kindProvider struct {
+	root    string
+	indexes map[string]int
+	sync.Mutex
+	clusterNames map[string]bool // Names of clusters created by this provider, they are never cleaned up.
+}
+
+type kindInstance struct {
+	sync.Mutex
+	manager        execmanager.ExecutionManager
+	root           string
+	id             string
+	clusterName    string
+	kind           string
+	images         []string
+	prepareScript  []string
+	factory        k8s.ValidationFactory
+	validator      k8s.KubernetesValidator
+	configLocation string
+	shellInterface shell.Manager
+	config         *config.ClusterProviderConfig
+	params         providers.InstanceOptions
+	started        bool
+}
+
+func (ki *kindInstance) GetID() string {
+	return ki.id
+}
+
+func (ki *kindInstance) CheckIsAlive() error {
+	ki.Lock()
+	defer ki.Unlock()
+	if ki.started {
+		return ki.validator.Validate()
+	}
+	return errors.New("cluster is not running")
+}
+
+func (ki *kindInstance) IsRunning() bool {
+	return ki.started
+}
+
+func (ki *kindInstance) GetClusterConfig() (string, error) {
+	if ki.started {
+		return ki.configLocation, nil
+	}
+	return "", errors.New("cluster is not started yet")
+}
+
+func (ki *kindInstance) GetRoot() string {
+	return ki.root
+}
+
+func (ki *kindInstance) Start(timeout time.Duration) (string, error) {
+	logrus.Infof("Starting cluster %s-%s", ki.config.Name, ki.id)
+
+	context, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	utils.ClearFolder(ki.root, true)
+
+	// Process and prepare environment variables
+	err := ki.shellInterface.ProcessEnvironment(
+		ki.id, ki.config.Name, ki.root, ki.config.Env,
+		map[string]string{
+			"kind-cluster-name": ki.clusterName,
+		})
+	if err != nil {
+		return "", err
+	}
+	ki.manager.AddLog(ki.id, "environment", ki.shellInterface.PrintEnv(ki.shellInterface.GetProcessedEnv()))
+
+	kindConfig := kindClusterConfig(ki.config)
+	utils.WriteFile(ki.root, kindConfigFile, kindConfig)
+	ki.manager.AddLog(ki.id, "kind-config", kindConfig)
+
+	ki.configLocation = path.Join(ki.root, kubeConfigFile)
+
+	createCmd := fmt.Sprintf("%s create cluster --name %s --config \"%s\" --kubeconfig \"%s\"",
+		ki.kind, ki.clusterName, path.Join(ki.root, kindConfigFile), ki.configLocation)
+	if image := ki.config.Parameters[nodeImageParameter]; image != "" {
+		createCmd += fmt.Sprintf(" --image %s", image)
+	}
+	if fileName, err := ki.shellInterface.RunCmd(context, "create", []string{createCmd}, nil); err != nil {
+		return fileName, err
+	}
+
+	if len(ki.images) > 0 {
+		var loadCmds []string
+		for _, image := range ki.images {
+			loadCmds = append(loadCmds, fmt.Sprintf("%s load docker-image %s --name %s", ki.kind, image, ki.clusterName))
+		}
+		if fileName, err := ki.shellInterface.RunCmd(context, "load-images", loadCmds, nil); err != nil {
+			return fileName, err
+		}
+	}
+
+	ki.Lock()
+	ki.validator, err = ki.factory.CreateValidator(ki.config, ki.configLocation)
+	ki.Unlock()
+	if err != nil {
+		logrus.Errorf("Failed to start validator %v", err)
+		return "", err
+	}
+
+	// Run prepare script
+	if !ki.params.NoPrepare {
+		if fileName, err := ki.shellInterface.RunCmd(context, "prepare", ki.prepareScript, []string{"KUBECONFIG=" + ki.configLocation}); err != nil {
+			return fileName, err
+		}
+	}
+
+	st := time.Now()
+	if err = ki.validator.WaitValid(context); err != nil {
+		logrus.Errorf("Failed to wait for required number of nodes: %v", err)
+		return "", err
+	}
+	logrus.Infof("Waiting for desired number of nodes complete %s-%s %v", ki.config.Name, ki.id, time.Since(st))
+
+	ki.started = true
+
+	return "", nil
+}
+
+func (ki *kindInstance) Destroy(timeout time.Duration) error {
+	logrus.Infof("Destroying cluster  %s", ki.id)
+
+	defer func() {
+		ki.Lock()
+		ki.started = false
+		ki.Unlock()
+	}()
+
+	if ki.params.NoStop {
+		logrus.Infof("Skipping stop of cluster %s", ki.id)
+		return nil
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	deleteCmd := fmt.Sprintf("%s delete cluster --name %s", ki.kind, ki.clusterName)
+	if _, err := ki.shellInterface.RunCmd(context, "delete", []string{deleteCmd}, nil); err != nil {
+		logrus.Errorf("Failed to delete kind cluster %s: %v", ki.clusterName, err)
+		return err
+	}
+	return nil
+}
+
+// kindClusterConfig - return inline kind configuration or generate one from node layout parameters.
+func kindClusterConfig(config *config.ClusterProviderConfig) string {
+	if inline, ok := config.Parameters[configParameter]; ok {
+		return inline
+	}
+
+	controlPlanes, _ := intParameter(config, controlPlanesParameter, 1)
+	workers, _ := intParameter(config, workersParameter, 0)
+
+	result := strings.Builder{}
+	_, _ = result.WriteString("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n")
+	for i := 0; i < controlPlanes; i++ {
+		_, _ = result.WriteString("- role: control-plane\n")
+	}
+	for i := 0; i < workers; i++ {
+		_, _ = result.WriteString("- role: worker\n")
+	}
+	return result.String()
+}
+
+func intParameter(config *config.ClusterProviderConfig, name string, defaultValue int) (int, error) {
+	value, ok := config.Parameters[name]
+	if !ok {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(value))
+}
+
+func boolParameter(config *config.ClusterProviderConfig, name string, defaultValue bool) (bool, error) {
+	value, ok := config.Parameters[name]
+	if !ok {
+		return defaultValue, nil
+	}
+	return strconv.ParseBool(strings.TrimSpace(value))
+}
+
+func stringParameter(config *config.ClusterProviderConfig, name, defaultValue string) string {
+	if value := strings.TrimSpace(config.Parameters[name]); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func (p *kindProvider) getProviderID(provider string) string {
+	val, ok := p.indexes[provider]
+	if ok {
+		val++
+	} else {
+		val = 1
+	}
+	p.indexes[provider] = val
+	return fmt.Sprintf("%d", val)
+}
+
+func (p *kindProvider) CreateCluster(config *config.ClusterProviderConfig, factory k8s.ValidationFactory,
+	manager execmanager.ExecutionManager,
+	instanceOptions providers.InstanceOptions) (providers.ClusterInstance, error) {
+	err := p.ValidateConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	p.Lock()
+	defer p.Unlock()
+	id := fmt.Sprintf("%s-%s", config.Name, p.getProviderID(config.Name))
+
+	clusterName := fmt.Sprintf("%s-%s", stringParameter(config, prefixParameter, defaultPrefix), utils.NewRandomStr(10))
+	p.clusterNames[clusterName] = true
+
+	clusterInstance := &kindInstance{
+		manager:        manager,
+		root:           path.Join(p.root, id),
+		id:             id,
+		clusterName:    clusterName,
+		kind:           stringParameter(config, kindParameter, defaultKind),
+		images:         strings.Fields(config.Parameters[imagesParameter]),
+		prepareScript:  utils.ParseScript(config.Scripts[prepareScript]),
+		config:         config,
+		factory:        factory,
+		shellInterface: shell.NewManager(manager, id, config, instanceOptions),
+		params:         instanceOptions,
+	}
+
+	return clusterInstance, nil
+}
+
+// CleanupClusters - Cleaning up leaked kind clusters matching provider prefix.
+// Kind clusters could not be labeled, so every cluster with matching prefix and not created by this provider
+// is considered as leaked, cleanup is enabled only if requested by 'cleanup-leaked' parameter.
+func (p *kindProvider) CleanupClusters(ctx context.Context, config *config.ClusterProviderConfig,
+	manager execmanager.ExecutionManager, instanceOptions providers.InstanceOptions) {
+	if cleanup, err := boolParameter(config, cleanupParameter, false); err != nil || !cleanup {
+		// Skip
+		return
+	}
+
+	clusterID := fmt.Sprintf("%s-cleanup", config.Name)
+
+	logrus.Infof("Starting cleaning up clusters for %s", config.Name)
+	shellInterface := shell.NewManager(manager, clusterID, config, instanceOptions)
+
+	if err := shellInterface.ProcessEnvironment(clusterID, config.Name, p.root, config.Env, nil); err != nil {
+		logrus.Warnf("Processing environment for cluster %s finished with error: %v", config.Name, err)
+		return
+	}
+
+	kind := stringParameter(config, kindParameter, defaultKind)
+	output, err := shellInterface.RunRead(ctx, "list-clusters", []string{kind + " get clusters"}, nil)
+	if err != nil {
+		logrus.Warnf("List clusters command for cluster %s finished with error: %v", config.Name, err)
+		return
+	}
+
+	prefix := stringParameter(config, prefixParameter, defaultPrefix) + "-"
+	var deleteCmds []string
+	for _, name := range strings.Split(output, "\n") {
+		name = strings.TrimSpace(name)
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		p.Lock()
+		owned := p.clusterNames[name]
+		p.Unlock()
+		if owned {
+			continue
+		}
+		logrus.Infof("Found leaked kind cluster %s", name)
+		deleteCmds = append(deleteCmds, fmt.Sprintf("%s delete cluster --name %s", kind, name))
+	}
+	if len(deleteCmds) == 0 {
+		return
+	}
+
+	if _, err := shellInterface.RunCmd(ctx, "cleanup", deleteCmds, nil); err != nil {
+		logrus.Warnf("Cleanup command for cluster %s finished with error: %v", config.Name, err)
+	}
+}
+
+// NewKindClusterProvider - Creates new kind provider
+func NewKindClusterProvider(root string) providers.ClusterProvider {
+	utils.ClearFolder(root, true)
+	return &kindProvider{
+		root:         root,
+		indexes:      map[string]int{},
+		clusterNames: map[string]bool{},
+	}
+}
+
+func (p *kindProvider) ValidateConfig(config *config.ClusterProviderConfig) error {
+	if !clusterNamePattern.MatchString(stringParameter(config, prefixParameter, defaultPrefix)) {
+		return errors.Errorf("invalid cluster name prefix %v", config.Parameters[prefixParameter])
+	}
+
+	_, hasControlPlanes := config.Parameters[controlPlanesParameter]
+	_, hasWorkers := config.Parameters[workersParameter]
+	if inline, ok := config.Parameters[configParameter]; ok {
+		if strings.TrimSpace(inline) == "" {
+			return errors.New("invalid kind config")
+		}
+		if hasControlPlanes || hasWorkers {
+			return errors.New("kind config and node layout could not be specified together")
+		}
+	}
+
+	controlPlanes, err := intParameter(config, controlPlanesParameter, 1)
+	if err != nil || controlPlanes < 1 {
+		return errors.Errorf("invalid number of control plane nodes %v", config.Parameters[controlPlanesParameter])
+	}
+	workers, err := intParameter(config, workersParameter, 0)
+	if err != nil || workers < 0 {
+		return errors.Errorf("invalid number of worker nodes %v", config.Parameters[workersParameter])
+	}
+	if _, err := boolParameter(config, cleanupParameter, false); err != nil {
+		return errors.Errorf("invalid cleanup-leaked value %v", config.Parameters[cleanupParameter])
+	}
+
+	for _, envVar := range config.EnvCheck {
+		envValue := os.Getenv(envVar)
+		if envValue == "" {
+			return errors.Errorf("environment variable are not specified %s Required variables: %v", envValue, config.EnvCheck)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kind
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/providers"
+	"github.com/networkservicemesh/cloudtest/pkg/tests"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+// fakeKindScript - a fake kind binary, keeps a list of clusters in a file and records all invocations.
+const fakeKindScript = `#!/bin/sh
+echo "$@" >> "%[1]s/calls"
+touch "%[1]s/clusters"
+cmd="$1 $2"
+shift 2
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --name) name="$2"; shift ;;
+    --kubeconfig) kubeconfig="$2"; shift ;;
+  esac
+  shift
+done
+case "$cmd" in
+  "create cluster")
+    echo "$name" >> "%[1]s/clusters"
+    echo "kubeconfig of $name" > "$kubeconfig" ;;
+  "delete cluster")
+    grep -v -x "$name" "%[1]s/clusters" > "%[1]s/clusters.tmp"
+    mv "%[1]s/clusters.tmp" "%[1]s/clusters" ;;
+  "get clusters")
+    cat "%[1]s/clusters" ;;
+esac
+`
+
+func setupFakeKind(t *testing.T, dir string) string {
+	binDir := path.Join(dir, "bin")
+	require.NoError(t, os.MkdirAll(binDir, os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(path.Join(binDir, "kind"), []byte(fmt.Sprintf(fakeKindScript, binDir)), 0700))
+	return binDir
+}
+
+func readLines(t *testing.T, fileName string) []string {
+	lines, err := utils.ReadFile(filepath.Clean(fileName))
+	require.NoError(t, err)
+	return lines
+}
+
+func TestKindProviderStartDestroy(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	binDir := setupFakeKind(t, tmpDir)
+	manager := execmanager.NewExecutionManager(path.Join(tmpDir, "results"))
+	provider := NewKindClusterProvider(path.Join(tmpDir, "kind"))
+
+	cfg := &config.ClusterProviderConfig{
+		Name: "kind",
+		Kind: "kind",
+		Parameters: map[string]string{
+			kindParameter:    path.Join(binDir, "kind"),
+			workersParameter: "2",
+			imagesParameter:  "image-a:latest image-b:latest",
+		},
+	}
+
+	instance, err := provider.CreateCluster(cfg, &tests.TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.NoError(t, err)
+	ki := instance.(*kindInstance)
+	require.True(t, strings.HasPrefix(ki.clusterName, "cloudtest-"))
+
+	_, err = instance.Start(time.Minute)
+	require.NoError(t, err)
+	require.True(t, instance.IsRunning())
+
+	kubeConfig, err := instance.GetClusterConfig()
+	require.NoError(t, err)
+	require.Equal(t, path.Join(instance.GetRoot(), kubeConfigFile), kubeConfig)
+	require.Equal(t, []string{"kubeconfig of " + ki.clusterName}, readLines(t, kubeConfig))
+
+	kindConfig := readLines(t, path.Join(instance.GetRoot(), kindConfigFile))
+	require.Equal(t, []string{
+		"kind: Cluster",
+		"apiVersion: kind.x-k8s.io/v1alpha4",
+		"nodes:",
+		"- role: control-plane",
+		"- role: worker",
+		"- role: worker",
+	}, kindConfig)
+
+	calls := readLines(t, path.Join(binDir, "calls"))
+	require.Len(t, calls, 3)
+	require.Contains(t, calls[1], "load docker-image image-a:latest --name "+ki.clusterName)
+	require.Contains(t, calls[2], "load docker-image image-b:latest --name "+ki.clusterName)
+	require.Equal(t, []string{ki.clusterName}, readLines(t, path.Join(binDir, "clusters")))
+
+	require.NoError(t, instance.Destroy(time.Minute))
+	require.False(t, instance.IsRunning())
+	require.Empty(t, readLines(t, path.Join(binDir, "clusters")))
+}
+
+func TestKindProviderInlineConfig(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	binDir := setupFakeKind(t, tmpDir)
+	manager := execmanager.NewExecutionManager(path.Join(tmpDir, "results"))
+	provider := NewKindClusterProvider(path.Join(tmpDir, "kind"))
+
+	inlineConfig := "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnetworking:\n  ipFamily: ipv6\n"
+	cfg := &config.ClusterProviderConfig{
+		Name: "kind",
+		Kind: "kind",
+		Parameters: map[string]string{
+			kindParameter:      path.Join(binDir, "kind"),
+			configParameter:    inlineConfig,
+			nodeImageParameter: "kindest/node:v1.20.2",
+		},
+	}
+
+	instance, err := provider.CreateCluster(cfg, &tests.TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.NoError(t, err)
+
+	_, err = instance.Start(time.Minute)
+	require.NoError(t, err)
+
+	content, err := ioutil.ReadFile(filepath.Clean(path.Join(instance.GetRoot(), kindConfigFile)))
+	require.NoError(t, err)
+	require.Equal(t, inlineConfig, string(content))
+
+	calls := readLines(t, path.Join(binDir, "calls"))
+	require.Len(t, calls, 1)
+	require.Contains(t, calls[0], "--image kindest/node:v1.20.2")
+}
+
+func TestKindProviderCleanup(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	binDir := setupFakeKind(t, tmpDir)
+
+	manager := execmanager.NewExecutionManager(path.Join(tmpDir, "results"))
+	provider := NewKindClusterProvider(path.Join(tmpDir, "kind"))
+
+	cfg := &config.ClusterProviderConfig{
+		Name: "kind",
+		Kind: "kind",
+		Parameters: map[string]string{
+			kindParameter:   path.Join(binDir, "kind"),
+			prefixParameter: "ci",
+		},
+	}
+
+	instance, err := provider.CreateCluster(cfg, &tests.TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.NoError(t, err)
+	owned := instance.(*kindInstance).clusterName
+
+	clusters := []string{"ci-leaked", owned, "other", "ci-leaked2"}
+	require.NoError(t, ioutil.WriteFile(path.Join(binDir, "clusters"), []byte(strings.Join(clusters, "\n")+"\n"), 0600))
+
+	// Cleanup is not requested, clusters of other runs are kept.
+	provider.CleanupClusters(context.Background(), cfg, manager, providers.InstanceOptions{})
+	require.Equal(t, clusters, readLines(t, path.Join(binDir, "clusters")))
+
+	cfg.Parameters[cleanupParameter] = "true"
+	provider.CleanupClusters(context.Background(), cfg, manager, providers.InstanceOptions{})
+
+	require.Equal(t, []string{owned, "other"}, readLines(t, path.Join(binDir, "clusters")))
+}
+
+func TestKindProviderValidateConfig(t *testing.T) {
+	provider := NewKindClusterProvider(path.Join(os.TempDir(), t.Name()))
+
+	require.NoError(t, provider.ValidateConfig(&config.ClusterProviderConfig{}))
+	require.Error(t, provider.ValidateConfig(&config.ClusterProviderConfig{
+		Parameters: map[string]string{
+			configParameter:  "kind: Cluster",
+			workersParameter: "2",
+		},
+	}))
+	require.Error(t, provider.ValidateConfig(&config.ClusterProviderConfig{
+		Parameters: map[string]string{
+			controlPlanesParameter: "0",
+		},
+	}))
+	require.Error(t, provider.ValidateConfig(&config.ClusterProviderConfig{
+		Parameters: map[string]string{
+			workersParameter: "two",
+		},
+	}))
+	require.Error(t, provider.ValidateConfig(&config.ClusterProviderConfig{
+		Parameters: map[string]string{
+			prefixParameter: "CI_Clusters",
+		},
+	}))
+	require.Error(t, provider.ValidateConfig(&config.ClusterProviderConfig{
+		Parameters: map[string]string{
+			cleanupParameter: "sometimes",
+		},
+	}))
+}
@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"os"
 	"path"
 	"strings"
@@ -56,6 +57,9 @@ const (
 	failedState       = "failed"
 )
 
+// devicePollInterval - an interval between device state checks during startup and deletion.
+var devicePollInterval = 10 * time.Second
+
 type packetProvider struct {
 	root    string
 	indexes map[string]int
@@ -93,6 +97,14 @@ type packetInstance struct {
 	virtualNetworkList       []packngo.VirtualNetwork
 	hardwareReservationsList []*packngo.HardwareReservation
 	facilitiesList           []string
+	portAssignments          []*portAssignment
+}
+
+// portAssignment - a VLAN assigned to a device port during setup, should be unassigned on destroy.
+type portAssignment struct {
+	deviceKey string
+	portID    string
+	vlanID    string
 }
 
 func (pi *packetInstance) GetID() string {
@@ -329,7 +341,7 @@ func (pi *packetInstance) waitDevicesStartup(context context.Context) error {
 			}
 		}
 		select {
-		case <-time.After(10 * time.Second):
+		case <-time.After(devicePollInterval):
 			continue
 		case <-context.Done():
 			log.Println("Timeout")
@@ -473,6 +485,11 @@ func (pi *packetInstance) setupDevicePorts(key string, portVLANs map[string]int)
 		if _, _, err = pi.client.Ports.Assign(port.ID, vlan.ID); err != nil {
 			return err
 		}
+		pi.portAssignments = append(pi.portAssignments, &portAssignment{
+			deviceKey: key,
+			portID:    port.ID,
+			vlanID:    vlan.ID,
+		})
 	}
 	return nil
 }
@@ -558,8 +575,118 @@ func (pi *packetInstance) findFacilities() ([]string, error) {
 	return facilitiesList, nil
 }
 
-func (pi *packetInstance) Destroy(_ time.Duration) error {
+func (pi *packetInstance) Destroy(timeout time.Duration) error {
 	logrus.Infof("Destroying cluster  %s", pi.id)
+
+	defer func() { pi.started = false }()
+
+	if pi.params.NoStop {
+		logrus.Infof("Skipping stop of cluster %s", pi.id)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_, file, err := pi.manager.OpenFile(pi.id, "destroy")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	log := utils.NewLogger(file)
+
+	var failed []string
+	if _, ok := pi.config.Scripts[stopScript]; ok {
+		var env []string
+		if pi.configLocation != "" {
+			env = append(env, "KUBECONFIG="+pi.configLocation)
+		}
+		if _, err = pi.shellInterface.RunCmd(ctx, "stop", pi.stopScript, env); err != nil {
+			log.Printf("Stop script error: %v\n", err)
+			failed = append(failed, fmt.Sprintf("stop script: %v", err))
+		}
+	}
+
+	if pi.client != nil {
+		failed = append(failed, pi.unassignDevicePorts(log)...)
+		failed = append(failed, pi.deleteDevices(ctx, log)...)
+		failed = append(failed, pi.deleteKey(log)...)
+	}
+
+	if len(failed) > 0 {
+		err = errors.Errorf("%s - failed to release resources: %s", pi.id, strings.Join(failed, ", "))
+		log.Println(err.Error())
+		logrus.Errorf(err.Error())
+		return err
+	}
+
+	log.Println("All resources released")
+	return nil
+}
+
+func (pi *packetInstance) unassignDevicePorts(log logrus.StdLogger) (failed []string) {
+	var left []*portAssignment
+	for _, pa := range pi.portAssignments {
+		log.Printf("Unassign port %v of %v from vlan %v\n", pa.portID, pa.deviceKey, pa.vlanID)
+		if _, _, err := pi.client.Ports.Unassign(pa.portID, pa.vlanID); err != nil {
+			log.Printf("error: %v\n", err)
+			failed = append(failed, fmt.Sprintf("port %v of %v: %v", pa.portID, pa.deviceKey, err))
+			left = append(left, pa)
+		}
+	}
+	pi.portAssignments = left
+	return failed
+}
+
+func (pi *packetInstance) deleteDevices(ctx context.Context, log logrus.StdLogger) (failed []string) {
+	deleting := map[string]string{}
+	for key, devID := range pi.devices {
+		log.Printf("Deleting device %v %v\n", key, devID)
+		if _, err := pi.client.Devices.Delete(devID, true); err != nil {
+			log.Printf("error: %v\n", err)
+			failed = append(failed, fmt.Sprintf("device %v (%v): %v", key, devID, err))
+			continue
+		}
+		deleting[key] = devID
+	}
+
+	for len(deleting) > 0 {
+		for key, devID := range deleting {
+			_, response, err := pi.client.Devices.Get(devID, nil)
+			if err != nil && response != nil && response.StatusCode == http.StatusNotFound {
+				log.Printf("Device %v %v is deleted\n", key, devID)
+				delete(deleting, key)
+				delete(pi.devices, key)
+			}
+		}
+		if len(deleting) == 0 {
+			break
+		}
+		select {
+		case <-time.After(devicePollInterval):
+			continue
+		case <-ctx.Done():
+			log.Println("Timeout")
+			for key, devID := range deleting {
+				failed = append(failed, fmt.Sprintf("device %v (%v): %v", key, devID, ctx.Err()))
+			}
+			return failed
+		}
+	}
+	return failed
+}
+
+func (pi *packetInstance) deleteKey(log logrus.StdLogger) []string {
+	if pi.sshKey == nil {
+		return nil
+	}
+	log.Printf("Deleting key %v %v\n", pi.sshKey.Label, pi.sshKey.ID)
+	if _, err := pi.client.SSHKeys.Delete(pi.sshKey.ID); err != nil {
+		log.Printf("error: %v\n", err)
+		return []string{fmt.Sprintf("ssh key %v (%v): %v", pi.sshKey.Label, pi.sshKey.ID, err)}
+	}
+	pi.sshKey = nil
+	pi.keyIds = nil
 	return nil
 }
 
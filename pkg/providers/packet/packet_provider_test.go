@@ -0,0 +1,203 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/packethost/packngo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/providers"
+	"github.com/networkservicemesh/cloudtest/pkg/shell"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+// fakePacket - a fake Packet API server keeping track of devices, keys and port assignments.
+type fakePacket struct {
+	sync.Mutex
+	devices       map[string]bool
+	keys          map[string]bool
+	unassigned    []string
+	failedDevices map[string]bool
+}
+
+func newFakePacket() *fakePacket {
+	return &fakePacket{
+		devices:       map[string]bool{},
+		keys:          map[string]bool{},
+		failedDevices: map[string]bool{},
+	}
+}
+
+func (f *fakePacket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 {
+		f.notFound(w)
+		return
+	}
+	resource, id := parts[0], parts[1]
+
+	switch {
+	case resource == "devices" && r.Method == http.MethodDelete:
+		if f.failedDevices[id] {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"errors":["Oh snap, something went wrong"]}`))
+			return
+		}
+		delete(f.devices, id)
+		w.WriteHeader(http.StatusNoContent)
+	case resource == "devices" && r.Method == http.MethodGet:
+		if !f.devices[id] {
+			f.notFound(w)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&packngo.Device{ID: id, State: activeState})
+	case resource == "ssh-keys" && r.Method == http.MethodDelete:
+		delete(f.keys, id)
+		w.WriteHeader(http.StatusNoContent)
+	case resource == "ports" && len(parts) == 3 && parts[2] == "unassign":
+		f.unassigned = append(f.unassigned, id)
+		_ = json.NewEncoder(w).Encode(&packngo.Port{ID: id})
+	default:
+		f.notFound(w)
+	}
+}
+
+func (f *fakePacket) notFound(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotFound)
+	_, _ = w.Write([]byte(`{"errors":["Not found"]}`))
+}
+
+func createTestInstance(t *testing.T, root string, server *httptest.Server, options providers.InstanceOptions) *packetInstance {
+	client, err := packngo.NewClientWithBaseURL("cloudtest", "token", nil, server.URL+"/")
+	require.NoError(t, err)
+
+	cfg := &config.ClusterProviderConfig{
+		Name: "packet",
+		Scripts: map[string]string{
+			"stop": "echo stopped",
+		},
+	}
+	manager := execmanager.NewExecutionManager(root)
+	return &packetInstance{
+		id:             "packet-1",
+		root:           root,
+		config:         cfg,
+		manager:        manager,
+		client:         client,
+		params:         options,
+		started:        true,
+		stopScript:     utils.ParseScript(cfg.Scripts[stopScript]),
+		shellInterface: shell.NewManager(manager, "packet-1", cfg, options),
+		devices: map[string]string{
+			"Master": "device-1",
+			"Worker": "device-2",
+		},
+		sshKey: &packngo.SSHKey{ID: "key-1", Label: "dev-ci-cloud-key"},
+		keyIds: []string{"key-1"},
+		portAssignments: []*portAssignment{
+			{deviceKey: "Worker", portID: "port-1", vlanID: "vlan-1"},
+		},
+	}
+}
+
+func TestPacketDestroy(t *testing.T) {
+	devicePollInterval = 10 * time.Millisecond
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	fake := newFakePacket()
+	fake.devices["device-1"] = true
+	fake.devices["device-2"] = true
+	fake.keys["key-1"] = true
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	pi := createTestInstance(t, tmpDir, server, providers.InstanceOptions{})
+
+	require.NoError(t, pi.Destroy(time.Minute))
+	require.False(t, pi.IsRunning())
+
+	require.Empty(t, fake.devices)
+	require.Empty(t, fake.keys)
+	require.Equal(t, []string{"port-1"}, fake.unassigned)
+
+	require.Empty(t, pi.devices)
+	require.Empty(t, pi.portAssignments)
+	require.Nil(t, pi.sshKey)
+}
+
+func TestPacketDestroyReportsFailures(t *testing.T) {
+	devicePollInterval = 10 * time.Millisecond
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	fake := newFakePacket()
+	fake.devices["device-1"] = true
+	fake.devices["device-2"] = true
+	fake.failedDevices["device-2"] = true
+	fake.keys["key-1"] = true
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	pi := createTestInstance(t, tmpDir, server, providers.InstanceOptions{})
+
+	err = pi.Destroy(time.Minute)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "device Worker (device-2)")
+	require.NotContains(t, err.Error(), "device-1")
+
+	require.Equal(t, map[string]bool{"device-2": true}, fake.devices)
+	require.Empty(t, fake.keys)
+	require.Equal(t, map[string]string{"Worker": "device-2"}, pi.devices)
+}
+
+func TestPacketDestroyNoStop(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	fake := newFakePacket()
+	fake.devices["device-1"] = true
+	fake.devices["device-2"] = true
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	pi := createTestInstance(t, tmpDir, server, providers.InstanceOptions{NoStop: true})
+
+	require.NoError(t, pi.Destroy(time.Minute))
+	require.Len(t, fake.devices, 2)
+	require.Empty(t, fake.unassigned)
+}
@@ -187,8 +187,31 @@ func (si *shellInstance) Start(timeout time.Duration) (string, error) {
 	return "", nil
 }
 
-func (si *shellInstance) Destroy(_ time.Duration) error {
+func (si *shellInstance) Destroy(timeout time.Duration) error {
 	logrus.Infof("Destroying cluster  %s", si.id)
+
+	defer func() {
+		si.Lock()
+		si.started = false
+		si.Unlock()
+	}()
+
+	if si.params.NoStop {
+		logrus.Infof("Skipping stop of cluster %s", si.id)
+		return nil
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var env []string
+	if si.configLocation != "" {
+		env = append(env, "KUBECONFIG="+si.configLocation)
+	}
+	if _, err := si.shellInterface.RunCmd(context, "stop", si.stopScript, env); err != nil {
+		logrus.Errorf("Failed to stop cluster %s: %v", si.id, err)
+		return err
+	}
 	return nil
 }
 
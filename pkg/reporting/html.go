@@ -0,0 +1,259 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporting
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// HTMLReport - a data of self-contained HTML report.
+type HTMLReport struct {
+	Title     string
+	Generated time.Time
+	Started   time.Time
+	Finished  time.Time
+	Tests     []*HTMLTest
+	Instances []*HTMLInstance
+}
+
+// HTMLTest - a test row of HTML report.
+type HTMLTest struct {
+	Name        string
+	Execution   string
+	Cluster     string
+	Status      string
+	Message     string
+	Duration    time.Duration
+	Attempts    []*HTMLAttempt
+	Artifacts   []*HTMLLink
+	Flaky       bool // Test is passed after failed attempts.
+	Quarantined bool // Test failures are not counted.
+}
+
+// HTMLAttempt - an execution attempt of test.
+type HTMLAttempt struct {
+	Index     int
+	Instances string
+	Status    string
+	Started   time.Time
+	Duration  time.Duration
+	Log       *HTMLLink
+	LogTail   string
+}
+
+// HTMLLink - a link to file or folder.
+type HTMLLink struct {
+	Name string
+	Href string
+}
+
+// HTMLInstance - a timeline row of cluster instance.
+type HTMLInstance struct {
+	ID       string
+	Provider string
+	State    string
+	Spans    []*HTMLSpan
+}
+
+// HTMLSpan - a timeline span, a cluster start or test execution.
+type HTMLSpan struct {
+	Kind     string // A span kind, used as CSS class, 'start', 'start-failed', 'success', 'failed', etc.
+	Label    string
+	Started  time.Time
+	Duration time.Duration
+}
+
+// Retries - return a number of retries of test.
+func (t *HTMLTest) Retries() int {
+	if len(t.Attempts) == 0 {
+		return 0
+	}
+	return len(t.Attempts) - 1
+}
+
+// Offset - return an offset of time on timeline in percents.
+func (r *HTMLReport) Offset(t time.Time) float64 {
+	total := r.Finished.Sub(r.Started)
+	if total <= 0 {
+		return 0
+	}
+	return float64(t.Sub(r.Started)) * 100 / float64(total)
+}
+
+// Width - return a width of duration on timeline in percents, spans are visible even if they are very short.
+func (r *HTMLReport) Width(d time.Duration) float64 {
+	total := r.Finished.Sub(r.Started)
+	if total <= 0 {
+		return 0
+	}
+	width := float64(d) * 100 / float64(total)
+	if width < 0.2 {
+		width = 0.2
+	}
+	return width
+}
+
+// Elapsed - return a duration of run.
+func (r *HTMLReport) Elapsed() time.Duration {
+	return r.Finished.Sub(r.Started).Round(time.Second)
+}
+
+// CountFlaky - return a number of flaky tests.
+func (r *HTMLReport) CountFlaky() int {
+	result := 0
+	for _, t := range r.Tests {
+		if t.Flaky {
+			result++
+		}
+	}
+	return result
+}
+
+// CountStatus - return a number of tests with given status.
+func (r *HTMLReport) CountStatus(status string) int {
+	count := 0
+	for _, t := range r.Tests {
+		if t.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"seconds": func(d time.Duration) string {
+		return d.Round(time.Millisecond).String()
+	},
+	"clock": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("15:04:05")
+	},
+	"millis": func(d time.Duration) int64 {
+		return d.Milliseconds()
+	},
+}).Parse(htmlReportTemplate))
+
+// WriteHTMLReport - write self-contained HTML report.
+func WriteHTMLReport(w io.Writer, report *HTMLReport) error {
+	if err := htmlTemplate.Execute(w, report); err != nil {
+		return errors.Wrap(err, "failed to generate HTML report")
+	}
+	return nil
+}
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; margin: 20px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { cursor: pointer; background: #f4f4f4; user-select: none; }
+pre { background: #f8f8f8; padding: 8px; max-height: 400px; overflow: auto; font-size: 12px; }
+.success { background: #5cb85c; }
+.failed, .timeout, .start-failed { background: #d9534f; }
+.skipped, .skipped-no-clusters { background: #aaa; }
+.start { background: #5bc0de; }
+.rerun-request, .retry-request { background: #f0ad4e; }
+td.status { color: #fff; font-weight: bold; }
+.badge { display: inline-block; margin-left: 4px; padding: 0 4px; border-radius: 3px; background: #777; font-size: 11px; }
+.timeline { position: relative; height: 22px; background: #f4f4f4; }
+.span { position: absolute; top: 2px; height: 18px; opacity: 0.85; overflow: hidden; font-size: 11px; color: #fff; white-space: nowrap; }
+.summary span { margin-right: 16px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="summary">
+<span>Generated: {{.Generated.Format "2006-01-02 15:04:05"}}</span>
+<span>Elapsed: {{.Elapsed}}</span>
+<span>Tests: {{len .Tests}}</span>
+<span>Passed: {{.CountStatus "success"}}</span>
+<span>Failed: {{.CountStatus "failed"}}</span>
+<span>Skipped: {{.CountStatus "skipped"}}</span>
+<span>Flaky: {{.CountFlaky}}</span>
+</div>
+
+<h2>Cluster timeline</h2>
+<table>
+<tr><th>Instance</th><th>State</th><th style="width: 75%">{{clock .Started}} - {{clock .Finished}}</th></tr>
+{{- range .Instances}}
+<tr><td>{{.ID}}</td><td>{{.State}}</td><td><div class="timeline">
+{{- range .Spans}}
+<div class="span {{.Kind}}" style="left: {{printf "%.3f" ($.Offset .Started)}}%; width: {{printf "%.3f" ($.Width .Duration)}}%" title="{{.Label}} {{clock .Started}} {{seconds .Duration}}">{{.Label}}</div>
+{{- end}}
+</div></td></tr>
+{{- end}}
+</table>
+
+<h2>Tests</h2>
+<table id="tests">
+<thead><tr><th>Name</th><th>Execution</th><th>Cluster</th><th>Status</th><th>Duration</th><th>Retries</th><th>Details</th></tr></thead>
+<tbody>
+{{- range .Tests}}
+<tr>
+<td>{{.Name}}</td>
+<td>{{.Execution}}</td>
+<td>{{.Cluster}}</td>
+<td class="status {{.Status}}">{{.Status}}{{if .Flaky}}<span class="badge">flaky</span>{{end}}{{if .Quarantined}}<span class="badge">quarantined</span>{{end}}</td>
+<td data-value="{{millis .Duration}}">{{seconds .Duration}}</td>
+<td>{{.Retries}}</td>
+<td>
+{{- if .Message}}<div>{{.Message}}</div>{{end}}
+{{- range .Artifacts}}<div><a href="{{.Href}}">{{.Name}}</a></div>{{end}}
+{{- range .Attempts}}
+<details><summary>Attempt {{.Index}}: {{.Status}} on {{.Instances}} at {{clock .Started}}, {{seconds .Duration}}{{if .Log}} (<a href="{{.Log.Href}}">{{.Log.Name}}</a>){{end}}</summary>
+<pre>{{.LogTail}}</pre>
+</details>
+{{- end}}
+</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+
+<script>
+document.querySelectorAll("#tests th").forEach(function (th, column) {
+  var ascending = true;
+  th.addEventListener("click", function () {
+    var body = document.querySelector("#tests tbody");
+    var rows = Array.prototype.slice.call(body.rows);
+    var value = function (row) {
+      var cell = row.cells[column];
+      return cell.dataset.value !== undefined ? Number(cell.dataset.value) : cell.textContent.trim();
+    };
+    rows.sort(function (a, b) {
+      var va = value(a), vb = value(b);
+      var result = typeof va === "number" && typeof vb === "number" ? va - vb : String(va).localeCompare(String(vb), undefined, {numeric: true});
+      return ascending ? result : -result;
+    });
+    ascending = !ascending;
+    rows.forEach(function (row) { body.appendChild(row); });
+  });
+});
+</script>
+</body>
+</html>
+`
@@ -16,7 +16,15 @@
 
 package reporting
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
 
 const (
 	// TimeCommentFormat is a format for printing readable time suite comment
@@ -26,7 +34,7 @@ const (
 // JUnitFile - JUnitFile
 type JUnitFile struct {
 	XMLName xml.Name `xml:"testsuites"`
-	Suites  []*Suite
+	Suites  []*Suite `xml:"testsuite"`
 }
 
 // Suite - Suite
@@ -38,8 +46,8 @@ type Suite struct {
 	Name        string      `xml:"name,attr"`
 	Properties  []*Property `xml:"properties>property,omitempty"`
 	TimeComment string      `xml:",comment"`
-	TestCases   []*TestCase
-	Suites      []*Suite
+	TestCases   []*TestCase `xml:"testcase"`
+	Suites      []*Suite    `xml:"testsuite"`
 }
 
 // SuiteDetails holds additional information about test suite.
@@ -50,13 +58,16 @@ type SuiteDetails struct {
 
 // TestCase - TestCase
 type TestCase struct {
-	XMLName     xml.Name     `xml:"testcase"`
-	Classname   string       `xml:"classname,attr"`
-	Name        string       `xml:"name,attr"`
-	Time        string       `xml:"time,attr"`
-	Cluster     string       `xml:"cluster_instance,attr"`
-	SkipMessage *SkipMessage `xml:"skipped,omitempty"`
-	Failure     *Failure     `xml:"failure,omitempty"`
+	XMLName       xml.Name     `xml:"testcase"`
+	Classname     string       `xml:"classname,attr"`
+	Name          string       `xml:"name,attr"`
+	Time          string       `xml:"time,attr"`
+	Cluster       string       `xml:"cluster_instance,attr"`
+	SkipMessage   *SkipMessage `xml:"skipped,omitempty"`
+	Failure       *Failure     `xml:"failure,omitempty"`
+	Flaky         bool         `xml:"flaky,attr,omitempty"`
+	FlakyFailures []*Failure   `xml:"flakyFailure,omitempty"`
+	Quarantined   bool         `xml:"quarantined,attr,omitempty"`
 }
 
 // SkipMessage - JUnitSkipMessage contains the reason why a testcase was skipped.
@@ -76,3 +87,40 @@ type Failure struct {
 	Type     string `xml:"type,attr"`
 	Contents string `xml:",chardata"`
 }
+
+// LoadJUnitFile - read JUnit report from file.
+func LoadJUnitFile(fileName string) (*JUnitFile, error) {
+	content, err := ioutil.ReadFile(filepath.Clean(fileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read JUnit report %s", fileName)
+	}
+	result := &JUnitFile{}
+	if err = xml.Unmarshal(content, result); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse JUnit report %s", fileName)
+	}
+	return result, nil
+}
+
+// TestDurations - return a maximum duration of every test case by test name.
+func (f *JUnitFile) TestDurations() map[string]time.Duration {
+	result := map[string]time.Duration{}
+	for _, s := range f.Suites {
+		s.collectDurations(result)
+	}
+	return result
+}
+
+func (s *Suite) collectDurations(result map[string]time.Duration) {
+	for _, tc := range s.TestCases {
+		if _, err := strconv.ParseFloat(tc.Time, 64); err != nil {
+			continue
+		}
+		duration := parseSeconds(tc.Time)
+		if known, ok := result[tc.Name]; !ok || duration > known {
+			result[tc.Name] = duration
+		}
+	}
+	for _, child := range s.Suites {
+		child.collectDurations(result)
+	}
+}
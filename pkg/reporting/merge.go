@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporting
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// MergeJUnitFiles - combine few JUnit reports into one, suites with same name are merged together,
+// their tests, failures and times are summed.
+func MergeJUnitFiles(files ...*JUnitFile) *JUnitFile {
+	result := &JUnitFile{}
+	for _, f := range files {
+		result.Suites = mergeSuites(result.Suites, f.Suites)
+	}
+	return result
+}
+
+func mergeSuites(target, source []*Suite) []*Suite {
+	for _, s := range source {
+		var existing *Suite
+		for _, t := range target {
+			if t.Name == s.Name {
+				existing = t
+				break
+			}
+		}
+		if existing == nil {
+			existing = &Suite{
+				Name:       s.Name,
+				Properties: s.Properties,
+			}
+			target = append(target, existing)
+		}
+		existing.merge(s)
+	}
+	return target
+}
+
+func (s *Suite) merge(source *Suite) {
+	s.Tests += source.Tests
+	s.Failures += source.Failures
+
+	duration := parseSeconds(s.Time) + parseSeconds(source.Time)
+	s.Time = fmt.Sprintf("%v", duration.Seconds())
+	s.TimeComment = fmt.Sprintf(TimeCommentFormat, duration.Round(time.Second))
+
+	s.TestCases = append(s.TestCases, source.TestCases...)
+	s.Suites = mergeSuites(s.Suites, source.Suites)
+}
+
+func parseSeconds(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporting
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Reporter - write test results in a specific format, every reporter is fed with same results model.
+type Reporter interface {
+	Write(w io.Writer, report *JUnitFile) error
+}
+
+// Reporter formats.
+const (
+	FormatJUnit     = "junit"
+	FormatTAP       = "tap"
+	FormatTest2JSON = "test2json"
+)
+
+var reporters = map[string]func() Reporter{
+	FormatJUnit:     NewJUnitReporter,
+	FormatTAP:       func() Reporter { return &tapReporter{} },
+	FormatTest2JSON: func() Reporter { return &test2jsonReporter{} },
+}
+
+// NewReporter - create reporter for given format.
+func NewReporter(format string) (Reporter, error) {
+	create, ok := reporters[format]
+	if !ok {
+		return nil, errors.Errorf("unknown report format %v, supported formats: %v", format, strings.Join(Formats(), ", "))
+	}
+	return create(), nil
+}
+
+// Formats - return a list of supported report formats.
+func Formats() []string {
+	var result []string
+	for format := range reporters {
+		result = append(result, format)
+	}
+	sort.Strings(result)
+	return result
+}
+
+type junitReporter struct{}
+
+// NewJUnitReporter - create reporter writing JUnit xml report.
+func NewJUnitReporter() Reporter {
+	return &junitReporter{}
+}
+
+func (r *junitReporter) Write(w io.Writer, report *JUnitFile) error {
+	output, err := xml.MarshalIndent(report, "  ", "    ")
+	if err != nil {
+		return errors.Wrap(err, "failed to store JUnit xml report")
+	}
+	_, err = w.Write(output)
+	return err
+}
+
+// reportCase - a test case with path of suites it belongs to.
+type reportCase struct {
+	path []string
+	*TestCase
+}
+
+// cases - return all test cases of report in order of appearance, a single summary suite is not included into path.
+func (f *JUnitFile) cases() []*reportCase {
+	var result []*reportCase
+	var walk func(path []string, s *Suite)
+	walk = func(path []string, s *Suite) {
+		for _, tc := range s.TestCases {
+			result = append(result, &reportCase{path: path, TestCase: tc})
+		}
+		for _, child := range s.Suites {
+			walk(append(append([]string{}, path...), child.Name), child)
+		}
+	}
+	for _, s := range f.Suites {
+		var path []string
+		if len(f.Suites) > 1 {
+			path = []string{s.Name}
+		}
+		walk(path, s)
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createReport() *JUnitFile {
+	return &JUnitFile{
+		Suites: []*Suite{{
+			Name: "All tests",
+			Suites: []*Suite{{
+				Name: "simple",
+				Suites: []*Suite{{
+					Name: "a_provider",
+					TestCases: []*TestCase{
+						{Name: "TestPass", Time: "1.5", Cluster: "a_provider-1"},
+						{Name: "TestFail", Time: "2", Cluster: "a_provider-1", Failure: &Failure{
+							Message:  "Test execution failed TestFail",
+							Contents: "first line\nsecond line\n",
+						}},
+						{Name: "TestSkip", Time: "0", SkipMessage: &SkipMessage{Message: "No #clusters"}},
+					},
+				}},
+			}},
+		}},
+	}
+}
+
+func TestNewReporter(t *testing.T) {
+	for _, format := range []string{FormatJUnit, FormatTAP, FormatTest2JSON} {
+		_, err := NewReporter(format)
+		require.NoError(t, err)
+	}
+	_, err := NewReporter("html")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "junit, tap, test2json")
+}
+
+func TestTAPReporter(t *testing.T) {
+	reporter, err := NewReporter(FormatTAP)
+	require.NoError(t, err)
+	output := &bytes.Buffer{}
+	require.NoError(t, reporter.Write(output, createReport()))
+
+	lines := strings.Split(output.String(), "\n")
+	require.Equal(t, "TAP version 13", lines[0])
+	require.Equal(t, "1..3", lines[1])
+	require.Contains(t, lines, "ok 1 - simple/a_provider/TestPass")
+	require.Contains(t, lines, "not ok 2 - simple/a_provider/TestFail")
+	require.Contains(t, lines, `ok 3 - simple/a_provider/TestSkip # SKIP No \#clusters`)
+	require.Contains(t, lines, "  duration_ms: 1500")
+	require.Contains(t, lines, `  message: "Test execution failed TestFail"`)
+	require.Contains(t, lines, "    second line")
+}
+
+func TestTest2JSONReporter(t *testing.T) {
+	reporter, err := NewReporter(FormatTest2JSON)
+	require.NoError(t, err)
+	output := &bytes.Buffer{}
+	require.NoError(t, reporter.Write(output, createReport()))
+
+	actions := map[string]string{}
+	pkgAction := ""
+	decoder := json.NewDecoder(output)
+	for decoder.More() {
+		event := &test2jsonEvent{}
+		require.NoError(t, decoder.Decode(event))
+		require.Equal(t, "simple/a_provider", event.Package)
+		if event.Action == "output" || event.Action == "run" {
+			continue
+		}
+		if event.Test == "" {
+			pkgAction = event.Action
+		} else {
+			actions[event.Test] = event.Action
+		}
+	}
+	require.Equal(t, map[string]string{"TestPass": "pass", "TestFail": "fail", "TestSkip": "skip"}, actions)
+	require.Equal(t, "fail", pkgAction)
+}
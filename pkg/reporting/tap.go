@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// tapReporter - write report in TAP version 13 format.
+type tapReporter struct{}
+
+func (r *tapReporter) Write(w io.Writer, report *JUnitFile) error {
+	cases := report.cases()
+	out := &strings.Builder{}
+	out.WriteString("TAP version 13\n")
+	out.WriteString(fmt.Sprintf("1..%d\n", len(cases)))
+	for i, tc := range cases {
+		name := strings.Join(append(append([]string{}, tc.path...), tc.Name), "/")
+		switch {
+		case tc.Failure != nil && tc.Quarantined:
+			// TODO directive keeps failure of quarantined test out of totals.
+			out.WriteString(fmt.Sprintf("not ok %d - %s # TODO quarantined\n", i+1, tapEscape(name)))
+		case tc.Failure != nil:
+			out.WriteString(fmt.Sprintf("not ok %d - %s\n", i+1, tapEscape(name)))
+		case tc.SkipMessage != nil:
+			out.WriteString(fmt.Sprintf("ok %d - %s # SKIP %s\n", i+1, tapEscape(name), tapEscape(tc.SkipMessage.Message)))
+		default:
+			out.WriteString(fmt.Sprintf("ok %d - %s\n", i+1, tapEscape(name)))
+		}
+		out.WriteString("  ---\n")
+		out.WriteString(fmt.Sprintf("  duration_ms: %d\n", parseSeconds(tc.Time).Milliseconds()))
+		if tc.Cluster != "" {
+			out.WriteString(fmt.Sprintf("  cluster: %q\n", tc.Cluster))
+		}
+		if tc.Flaky {
+			out.WriteString("  flaky: true\n")
+		}
+		if tc.Quarantined {
+			out.WriteString("  quarantined: true\n")
+		}
+		if tc.Failure != nil {
+			out.WriteString(fmt.Sprintf("  message: %q\n", tc.Failure.Message))
+			if tc.Failure.Contents != "" {
+				out.WriteString("  output: |\n")
+				for _, line := range strings.Split(strings.TrimRight(tc.Failure.Contents, "\n"), "\n") {
+					out.WriteString("    " + line + "\n")
+				}
+			}
+		}
+		out.WriteString("  ...\n")
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// tapEscape - escape characters having special meaning in TAP test line.
+func tapEscape(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "#", "\\#")
+	return strings.ReplaceAll(value, "\n", " ")
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// test2jsonEvent - an event of 'go test -json' stream, see 'go doc test2json'.
+type test2jsonEvent struct {
+	Time    *time.Time `json:",omitempty"`
+	Action  string
+	Package string   `json:",omitempty"`
+	Test    string   `json:",omitempty"`
+	Elapsed *float64 `json:",omitempty"`
+	Output  *string  `json:",omitempty"`
+}
+
+// test2jsonReporter - write report as 'go test -json' stream, every suite path is reported as a package,
+// so tools like gotestsum and tparse could render it.
+type test2jsonReporter struct{}
+
+func (r *test2jsonReporter) Write(w io.Writer, report *JUnitFile) error {
+	encoder := json.NewEncoder(w)
+	emit := func(event *test2jsonEvent) error {
+		return encoder.Encode(event)
+	}
+	output := func(pkg, test, line string) error {
+		return emit(&test2jsonEvent{Action: "output", Package: pkg, Test: test, Output: &line})
+	}
+
+	type packageState struct {
+		name    string
+		failed  bool
+		elapsed float64
+	}
+	var packages []*packageState
+	byName := map[string]*packageState{}
+
+	for _, tc := range report.cases() {
+		pkgName := strings.Join(tc.path, "/")
+		pkg, ok := byName[pkgName]
+		if !ok {
+			pkg = &packageState{name: pkgName}
+			byName[pkgName] = pkg
+			packages = append(packages, pkg)
+		}
+		elapsed := parseSeconds(tc.Time).Seconds()
+		pkg.elapsed += elapsed
+
+		if err := emit(&test2jsonEvent{Action: "run", Package: pkgName, Test: tc.Name}); err != nil {
+			return err
+		}
+		if err := output(pkgName, tc.Name, fmt.Sprintf("=== RUN   %s\n", tc.Name)); err != nil {
+			return err
+		}
+		action, result := "pass", "PASS"
+		switch {
+		case tc.Failure != nil:
+			action, result = "fail", "FAIL"
+			pkg.failed = pkg.failed || !tc.Quarantined
+			for _, line := range strings.Split(strings.TrimRight(tc.Failure.Contents, "\n"), "\n") {
+				if line == "" {
+					continue
+				}
+				if err := output(pkgName, tc.Name, "    "+line+"\n"); err != nil {
+					return err
+				}
+			}
+		case tc.SkipMessage != nil:
+			action, result = "skip", "SKIP"
+			if err := output(pkgName, tc.Name, fmt.Sprintf("    %s\n", tc.SkipMessage.Message)); err != nil {
+				return err
+			}
+		}
+		if tc.Flaky {
+			if err := output(pkgName, tc.Name, "    flaky: passed after failed attempts\n"); err != nil {
+				return err
+			}
+		}
+		if tc.Quarantined {
+			if err := output(pkgName, tc.Name, "    quarantined: failures are not counted\n"); err != nil {
+				return err
+			}
+		}
+		if err := output(pkgName, tc.Name, fmt.Sprintf("--- %s: %s (%.2fs)\n", result, tc.Name, elapsed)); err != nil {
+			return err
+		}
+		if err := emit(&test2jsonEvent{Action: action, Package: pkgName, Test: tc.Name, Elapsed: &elapsed}); err != nil {
+			return err
+		}
+	}
+
+	for _, pkg := range packages {
+		action, result := "pass", "ok  "
+		if pkg.failed {
+			action, result = "fail", "FAIL"
+		}
+		elapsed := pkg.elapsed
+		if err := output(pkg.name, "", fmt.Sprintf("%s\t%s\t%.3fs\n", result, pkg.name, elapsed)); err != nil {
+			return err
+		}
+		if err := emit(&test2jsonEvent{Action: action, Package: pkg.name, Elapsed: &elapsed}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runners
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const containerKillTimeout = 30 * time.Second
+
+var containerNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// Container - a docker container test commands are executed in, so results do not depend on host toolchain.
+type Container struct {
+	Image   string   // A docker image to run commands in.
+	Options []string // Additional options of docker run.
+	Root    string   // A folder mounted to container at the same path, a module root of package.
+	Dir     string   // A working directory inside of container, a package root.
+
+	name    string
+	counter int32
+}
+
+// containerRunner - a runner is able to execute its commands inside of container.
+type containerRunner interface {
+	setContainer(container *Container)
+}
+
+// NewContainer - creates a container to run commands of test, module root of test package is mounted
+// to container, so go test is able to build the package.
+func NewContainer(ids string, test *model.TestEntry) *Container {
+	dir, err := filepath.Abs(test.PackageDir())
+	if err != nil {
+		dir = test.PackageDir()
+	}
+	root := dir
+	for p := dir; ; p = filepath.Dir(p) {
+		if utils.FileExists(filepath.Join(p, "go.mod")) {
+			root = p
+			break
+		}
+		if filepath.Dir(p) == p {
+			break
+		}
+	}
+	return &Container{
+		Image:   test.ExecutionConfig.Image,
+		Options: test.ExecutionConfig.ImageOptions,
+		Root:    root,
+		Dir:     dir,
+		name:    containerNameRegexp.ReplaceAllString(fmt.Sprintf("cloudtest-%s-%d", ids, time.Now().UnixNano()), "_"),
+	}
+}
+
+// InContainer - make runner to execute its commands inside of container, runner is returned as is if it does not
+// support containers.
+func InContainer(runner TestRunner, container *Container) TestRunner {
+	if r, ok := runner.(containerRunner); ok {
+		r.setContainer(container)
+	} else {
+		logrus.Warnf("Test runner %T does not support containers, commands are executed on host", runner)
+	}
+	return runner
+}
+
+// WrapArgs - return docker command running args inside of container and a function to be called when command is
+// finished. Variables of env are passed by name, absolute paths they refer are mounted to container at the same paths.
+// Container is killed if ctx is done before command is finished.
+func (c *Container) WrapArgs(ctx context.Context, args, env []string) (result []string, done func()) {
+	if c == nil {
+		return args, func() {}
+	}
+	name := fmt.Sprintf("%s-%d", c.name, atomic.AddInt32(&c.counter, 1))
+	result = []string{"docker", "run", "--rm", "--init", "--name", name, "--network", "host",
+		"-v", c.Root + ":" + c.Root, "-w", c.Dir}
+
+	mounts := map[string]bool{c.Root: true}
+	seen := map[string]bool{}
+	for _, e := range env {
+		key, value, err := utils.ParseVariable(e)
+		if err != nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, "-e", key)
+		for _, p := range filepath.SplitList(value) {
+			if !filepath.IsAbs(p) || mounts[p] || !utils.FileExists(p) {
+				continue
+			}
+			mounts[p] = true
+			result = append(result, "-v", p+":"+p)
+		}
+	}
+	result = append(append(append(result, c.Options...), c.Image), args...)
+	return result, c.watch(ctx, name)
+}
+
+// WrapCmdLine - the same as WrapArgs for command line.
+func (c *Container) WrapCmdLine(ctx context.Context, cmdLine string, env []string) (result string, done func()) {
+	if c == nil {
+		return cmdLine, func() {}
+	}
+	args, done := c.WrapArgs(ctx, nil, env)
+	quoted := make([]string, 0, len(args))
+	for _, a := range args {
+		quoted = append(quoted, quoteArg(a))
+	}
+	return strings.Join(quoted, " ") + " " + cmdLine, done
+}
+
+// Describe - return a command line description with container image.
+func (c *Container) Describe(cmdLine string) string {
+	if c == nil {
+		return cmdLine
+	}
+	return fmt.Sprintf("docker run %s %s", c.Image, cmdLine)
+}
+
+func (c *Container) watch(ctx context.Context, name string) func() {
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			logrus.Infof("Killing container %s: %v", name, ctx.Err())
+			killCtx, cancel := context.WithTimeout(context.Background(), containerKillTimeout)
+			defer cancel()
+			if out, err := exec.CommandContext(killCtx, "docker", "kill", name).CombinedOutput(); err != nil {
+				logrus.Warnf("Failed to kill container %s: %v %s", name, err, out)
+			}
+		case <-finished:
+		}
+	}()
+	return func() { close(finished) }
+}
+
+func quoteArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"\\'") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
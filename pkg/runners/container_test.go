@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runners_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/runners"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestContainerWrapArgs(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), t.Name())
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	pkgDir := path.Join(tmpDir, "test", "e2e")
+	require.NoError(t, os.MkdirAll(pkgDir, os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(path.Join(tmpDir, "go.mod"), []byte("module sample\n"), os.ModePerm))
+	kubeconfig := path.Join(tmpDir, "config")
+	require.NoError(t, ioutil.WriteFile(kubeconfig, []byte("apiVersion: v1\n"), os.ModePerm))
+
+	container := runners.NewContainer("a_provider-1", &model.TestEntry{
+		Package: "e2e",
+		ExecutionConfig: &config.Execution{
+			PackageRoot:  path.Join(tmpDir, "test"),
+			Image:        "golang:1.15",
+			ImageOptions: []string{"--privileged"},
+		},
+	})
+	require.Equal(t, tmpDir, container.Root)
+	require.Equal(t, pkgDir, container.Dir)
+
+	args, done := container.WrapArgs(context.Background(), []string{"go", "test", "."},
+		[]string{"KUBECONFIG=" + kubeconfig, "ARTIFACTS_DIR=" + tmpDir, "NAME=value"})
+	defer done()
+	require.Equal(t, []string{"docker", "run", "--rm", "--init"}, args[:4])
+	require.Subset(t, args, []string{"-e", "KUBECONFIG", "ARTIFACTS_DIR", "NAME", kubeconfig + ":" + kubeconfig,
+		tmpDir + ":" + tmpDir, "-w", pkgDir, "--privileged"})
+	require.Equal(t, []string{"golang:1.15", "go", "test", "."}, args[len(args)-4:])
+
+	cmdLine, done := container.WrapCmdLine(context.Background(), "make test", nil)
+	defer done()
+	parsed := utils.ParseCommandLine(cmdLine)
+	require.Equal(t, []string{"golang:1.15", "make", "test"}, parsed[len(parsed)-3:])
+}
+
+func TestNoContainer(t *testing.T) {
+	var container *runners.Container
+	args, done := container.WrapArgs(context.Background(), []string{"go", "test"}, []string{"A=B"})
+	done()
+	require.Equal(t, []string{"go", "test"}, args)
+	require.Equal(t, "go test", container.Describe("go test"))
+}
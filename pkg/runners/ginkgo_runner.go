@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runners
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/networkservicemesh/cloudtest/pkg/ginkgo"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/shell"
+)
+
+// GinkgoRunner - runs a subset of Ginkgo suite specs and writes Ginkgo JSON report.
+type GinkgoRunner struct {
+	args       []string
+	envManager shell.EnvironmentManager
+	test       *model.TestEntry
+	container  *Container
+}
+
+// Run - run Ginkgo suite focused on task specs, arguments are passed as is, so spec texts are not parsed by shell.
+func (g *GinkgoRunner) Run(ctx context.Context, envs []string, writer *bufio.Writer) error {
+	envs = append(g.envManager.GetProcessedEnv(), envs...)
+	args, done := g.container.WrapArgs(ctx, g.args, envs)
+	defer done()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = g.test.PackageDir()
+	cmd.Env = append(os.Environ(), envs...)
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	err := cmd.Run()
+	_ = writer.Flush()
+	return err
+}
+
+// GetCmdLine - return created command line.
+func (g *GinkgoRunner) GetCmdLine() string {
+	return g.container.Describe(strings.Join(g.args, " "))
+}
+
+func (g *GinkgoRunner) setContainer(container *Container) {
+	g.container = container
+}
+
+var _ TestRunner = (*GinkgoRunner)(nil)
+
+// NewGinkgoRunner - creates Ginkgo runner, spec results are written to reportFile.
+func NewGinkgoRunner(ids string, test *model.TestEntry, timeout time.Duration, reportFile string) *GinkgoRunner {
+	args := []string{"go", "test", ".", "-test.timeout", timeout.String(), "-count", "1", "-tags", test.Tags,
+		"-ginkgo.v", "-ginkgo.focus=" + ginkgo.FocusPattern(test.Suite.Tests), "-ginkgo.json-report=" + reportFile}
+	envMgr := shell.NewEnvironmentManager()
+	_ = envMgr.ProcessEnvironment(ids, "ginkgo", os.TempDir(), test.ExecutionConfig.Env, map[string]string{})
+	return &GinkgoRunner{
+		args:       args,
+		envManager: envMgr,
+		test:       test,
+	}
+}
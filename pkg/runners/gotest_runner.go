@@ -31,27 +31,38 @@ import (
 )
 
 type goTestRunner struct {
-	test    *model.TestEntry
-	cmdLine string
-	envMgr  shell.EnvironmentManager
+	test      *model.TestEntry
+	cmdLine   string
+	envMgr    shell.EnvironmentManager
+	container *Container
 }
 
 func (runner *goTestRunner) Run(timeoutCtx context.Context, env []string, writer *bufio.Writer) error {
 	logger := func(s string) {}
 	cmdEnv := append(runner.envMgr.GetProcessedEnv(), env...)
-	_, err := utils.RunCommand(timeoutCtx, runner.cmdLine, runner.test.ExecutionConfig.PackageRoot,
+	cmdLine, done := runner.container.WrapCmdLine(timeoutCtx, runner.cmdLine, cmdEnv)
+	defer done()
+	_, err := utils.RunCommand(timeoutCtx, cmdLine, runner.test.PackageDir(),
 		logger, writer, cmdEnv, nil, false)
 	return err
 }
 
 func (runner *goTestRunner) GetCmdLine() string {
-	return runner.cmdLine
+	return runner.container.Describe(runner.cmdLine)
 }
 
-// NewGoTestRunner - creates go test runner
-func NewGoTestRunner(ids string, test *model.TestEntry, timeout time.Duration) TestRunner {
+func (runner *goTestRunner) setContainer(container *Container) {
+	runner.container = container
+}
+
+// NewGoTestRunner - creates go test runner, if binary is passed the precompiled test binary is executed instead of go test.
+func NewGoTestRunner(ids string, test *model.TestEntry, timeout time.Duration, binary string) TestRunner {
 	cmdLine := fmt.Sprintf(`go test . -test.timeout %v -count 1 --run "^(%s)$\\z" --tags "%s" --test.v`,
 		timeout, test.Name, test.Tags)
+	if binary != "" {
+		cmdLine = fmt.Sprintf(`"%s" -test.timeout %v -test.count 1 -test.run "^(%s)$\\z" -test.v`,
+			binary, timeout, test.Name)
+	}
 
 	envMgr := shell.NewEnvironmentManager()
 	_ = envMgr.ProcessEnvironment(ids, "gotest", os.TempDir(), test.ExecutionConfig.Env, map[string]string{})
@@ -31,9 +31,10 @@ import (
 )
 
 type shellTestRunner struct {
-	test   *model.TestEntry
-	envMgr shell.EnvironmentManager
-	id     string
+	test      *model.TestEntry
+	envMgr    shell.EnvironmentManager
+	id        string
+	container *Container
 }
 
 func (runner *shellTestRunner) Run(timeoutCtx context.Context, env []string, writer *bufio.Writer) error {
@@ -53,7 +54,9 @@ func (runner *shellTestRunner) runCmd(context context.Context, script, env []str
 
 		logger := func(s string) {
 		}
-		_, err := utils.RunCommand(context, cmd, "", logger, writer, cmdEnv, nil, false)
+		cmdLine, done := runner.container.WrapCmdLine(context, cmd, cmdEnv)
+		_, err := utils.RunCommand(context, cmdLine, "", logger, writer, cmdEnv, nil, false)
+		done()
 		if err != nil {
 			_, _ = writer.WriteString(fmt.Sprintf("error running command: %v\n", err))
 			_ = writer.Flush()
@@ -64,7 +67,11 @@ func (runner *shellTestRunner) runCmd(context context.Context, script, env []str
 }
 
 func (runner *shellTestRunner) GetCmdLine() string {
-	return runner.test.RunScript
+	return runner.container.Describe(runner.test.RunScript)
+}
+
+func (runner *shellTestRunner) setContainer(container *Container) {
+	runner.container = container
 }
 
 // NewShellTestRunner - creates a new shell script test runner.
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -34,30 +35,49 @@ type SuiteRunner struct {
 	cmd        string
 	envManager shell.EnvironmentManager
 	test       *model.TestEntry
+	container  *Container
 }
 
 func (s *SuiteRunner) Run(ctx context.Context, envs []string, writer *bufio.Writer) error {
+	cmd, done := s.container.WrapCmdLine(ctx, s.cmd, append(envs, s.envManager.GetProcessedEnv()...))
+	defer done()
 	envs = append(append(envs, s.envManager.GetProcessedEnv()...), os.Environ()...)
-	err := exechelper.Run(s.cmd,
+	err := exechelper.Run(cmd,
 		exechelper.WithStdout(writer),
 		exechelper.WithStderr(writer),
 		exechelper.WithContext(ctx),
-		exechelper.WithDir(s.test.ExecutionConfig.PackageRoot),
+		exechelper.WithDir(s.test.PackageDir()),
 		exechelper.WithEnvirons(envs...),
 	)
 	return err
 }
 
 func (s *SuiteRunner) GetCmdLine() string {
-	return s.cmd
+	return s.container.Describe(s.cmd)
+}
+
+func (s *SuiteRunner) setContainer(container *Container) {
+	s.container = container
 }
 
 var _ TestRunner = (*SuiteRunner)(nil)
 
-func NewSuiteRunner(ids string, test *model.TestEntry, timeout time.Duration) *SuiteRunner {
-	pattern := strings.Join(test.Suite.Tests, "|")
-	cmdLine := fmt.Sprintf(`go test . -test.timeout %v -count 1 -json --run "^(%s)$\\z" --tags "%s" --test.v --testify.m="%v"`,
-		timeout, test.Suite.Name, test.Tags, pattern)
+func NewSuiteRunner(ids string, test *model.TestEntry, timeout time.Duration, binary string) *SuiteRunner {
+	run := fmt.Sprintf(`^(%s)$\\z`, test.Suite.Name)
+	var filter string
+	if test.Kind == model.SubtestsTestKind {
+		// Subtests are selected by the second level of run pattern.
+		run += fmt.Sprintf(`/^(%s)$\\z`, subtestsPattern(test.Suite.Tests))
+	} else {
+		filter = fmt.Sprintf(` --testify.m="%v"`, strings.Join(test.Suite.Tests, "|"))
+	}
+	cmdLine := fmt.Sprintf(`go test . -test.timeout %v -count 1 -json --run "%s" --tags "%s" --test.v%s`,
+		timeout, run, test.Tags, filter)
+	if binary != "" {
+		// test2json produces the same JSON events as go test -json.
+		cmdLine = fmt.Sprintf(`go tool test2json -t "%s" -test.timeout %v -test.count 1 -test.run "%s" -test.v%s`,
+			binary, timeout, run, filter)
+	}
 	envMgr := shell.NewEnvironmentManager()
 	_ = envMgr.ProcessEnvironment(ids, "gotest", os.TempDir(), test.ExecutionConfig.Env, map[string]string{})
 	return &SuiteRunner{
@@ -66,3 +86,12 @@ func NewSuiteRunner(ids string, test *model.TestEntry, timeout time.Duration) *S
 		envManager: envMgr,
 	}
 }
+
+// subtestsPattern - return a run pattern matching exactly given subtests, escaped to be used inside of quoted argument.
+func subtestsPattern(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		quoted = append(quoted, regexp.QuoteMeta(n))
+	}
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(strings.Join(quoted, "|"))
+}
@@ -24,14 +24,18 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
 	"github.com/networkservicemesh/cloudtest/pkg/model"
 	"github.com/networkservicemesh/cloudtest/pkg/suites/lookup"
 )
 
 const noneParseFlag = 0
 
-// Find finds go test suites recursively in root
-func Find(root string) (suites []*model.Suite, err error) {
+// Find finds go test suites recursively in root, test files are filtered by build tags. Tests calling suite.Run with
+// a suite could not be resolved are reported as warnings
+func Find(root string, tags []string) (suites []*model.Suite, err error) {
 	var testPaths []string
 	if err = filepath.Walk(root,
 		func(path string, info os.FileInfo, err error) error {
@@ -48,84 +52,118 @@ func Find(root string) (suites []*model.Suite, err error) {
 	}
 
 	fileSet := token.NewFileSet()
-	resolvedImports := lookup.ResolvedImports()
+	loader := lookup.NewLoader(root, tags)
 
-	for _, p := range testPaths {
-		pkgNodes, err := parser.ParseDir(fileSet, p, func(info os.FileInfo) bool {
-			return strings.HasSuffix(info.Name(), "_test.go")
-		}, noneParseFlag)
+	for _, dir := range testPaths {
+		dirSuites, err := findInDir(fileSet, loader, dir)
 		if err != nil {
 			return nil, err
 		}
+		suites = append(suites, dirSuites...)
+	}
 
-		for _, pkgNode := range pkgNodes {
-			pkg := lookup.NewPackage(pkgNode, resolvedImports)
-			for i := range pkg.Files {
-				file := pkg.Files[i]
-				if err = forEachTest(file, func(funcDecl *ast.FuncDecl) (err error) {
-					var suite *lookup.Suite
-					pkgName, suiteName := findSuiteNameInBody(funcDecl.Body)
-					if pkgName != "" {
-						suite, err = file.Lookup(pkgName, suiteName)
-					} else if suiteName != "" {
-						suite, err = pkg.Lookup(suiteName)
-					}
-					if suite != nil {
-						suites = append(suites, &model.Suite{
-							Name:  funcDecl.Name.Name,
-							Tests: suite.GetTests(),
-						})
-					}
-					return err
-				}); err != nil {
-					return nil, err
-				}
+	return suites, nil
+}
+
+// FindPackage finds go test suites of a single package in dir, nested packages are not scanned. Test files are
+// filtered by build tags.
+func FindPackage(dir string, tags []string) ([]*model.Suite, error) {
+	return findInDir(token.NewFileSet(), lookup.NewLoader(dir, tags), dir)
+}
+
+func findInDir(fileSet *token.FileSet, loader *lookup.Loader, dir string) (suites []*model.Suite, err error) {
+	// Non-test files are parsed too, since suites could be declared in them.
+	pkgNodes, err := parser.ParseDir(fileSet, dir, func(info os.FileInfo) bool {
+		return strings.HasSuffix(info.Name(), ".go") && loader.MatchFile(dir, info.Name())
+	}, noneParseFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkgNode := range pkgNodes {
+		pkg := lookup.NewPackage(pkgNode, loader)
+		for i := range pkg.Files {
+			file := pkg.Files[i]
+			if !strings.HasSuffix(fileSet.Position(file.Package).Filename, "_test.go") {
+				continue
 			}
+			forEachTest(file, func(funcDecl *ast.FuncDecl) {
+				suiteArg := findSuiteInBody(funcDecl.Body)
+				if suiteArg == nil {
+					return
+				}
+				suite, err := lookupSuite(pkg, file, suiteArg)
+				if err == nil && suite == nil {
+					err = errors.New("suite type is not found")
+				}
+				if err != nil {
+					logrus.Warnf("Failed to resolve suite of %s in %s, it is executed as a single test: %v",
+						funcDecl.Name.Name, dir, err)
+					return
+				}
+				suites = append(suites, &model.Suite{
+					Name:  funcDecl.Name.Name,
+					Tests: suite.GetTests(),
+				})
+			})
 		}
 	}
-
 	return suites, nil
 }
 
-func forEachTest(file *lookup.File, applier func(funcDecl *ast.FuncDecl) error) error {
+func lookupSuite(pkg *lookup.Package, file *lookup.File, suiteArg ast.Expr) (*lookup.Suite, error) {
+	pkgName, name, constructor := findExpressionName(suiteArg)
+	switch {
+	case name == "":
+		return nil, errors.New("unsupported suite expression")
+	case constructor && pkgName != "":
+		return file.LookupConstructor(pkgName, name)
+	case constructor:
+		return pkg.LookupConstructor(name)
+	case pkgName != "":
+		return file.Lookup(pkgName, name)
+	default:
+		return pkg.Lookup(name)
+	}
+}
+
+func forEachTest(file *lookup.File, applier func(funcDecl *ast.FuncDecl)) {
 	for _, decl := range file.Decls {
 		funcDecl, ok := decl.(*ast.FuncDecl)
-		if !ok {
+		if !ok || !isTestFunc(funcDecl) {
 			continue
 		}
 
-		// func Test...(... *testing.T) {...}
-		if !strings.HasPrefix(funcDecl.Name.Name, "Test") {
-			continue
-		}
-		params := funcDecl.Type.Params.List
-		if len(params) != 1 {
-			continue
-		}
-		ptrTestingT, ok := params[0].Type.(*ast.StarExpr)
-		if !ok {
-			continue
-		}
-		testingT, ok := ptrTestingT.X.(*ast.SelectorExpr)
-		if !ok {
-			continue
-		}
-		testing, ok := testingT.X.(*ast.Ident)
-		if !ok {
-			continue
-		}
-		if testing.Name != "testing" || testingT.Sel.Name != "T" {
-			continue
-		}
+		applier(funcDecl)
+	}
+}
 
-		if err := applier(funcDecl); err != nil {
-			return err
-		}
+// isTestFunc - return true for func Test...(... *testing.T) {...}
+func isTestFunc(funcDecl *ast.FuncDecl) bool {
+	if !strings.HasPrefix(funcDecl.Name.Name, "Test") || funcDecl.Body == nil {
+		return false
 	}
-	return nil
+	params := funcDecl.Type.Params.List
+	if len(params) != 1 {
+		return false
+	}
+	ptrTestingT, ok := params[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	testingT, ok := ptrTestingT.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	testing, ok := testingT.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	return testing.Name == "testing" && testingT.Sel.Name == "T"
 }
 
-func findSuiteNameInBody(body *ast.BlockStmt) (pkgName, name string) {
+// findSuiteInBody returns a suite argument of suite.Run call in test body, or nil if there is no such call
+func findSuiteInBody(body *ast.BlockStmt) ast.Expr {
 	for _, l := range body.List {
 		var expr *ast.ExprStmt
 		var ok bool
@@ -143,48 +181,63 @@ func findSuiteNameInBody(body *ast.BlockStmt) (pkgName, name string) {
 		if selector, ok = call.Fun.(*ast.SelectorExpr); !ok {
 			continue
 		}
-		if selector.X.(*ast.Ident).Name == "suite" && selector.Sel.Name == "Run" {
-			return findExpressionName(call.Args[1])
+		if x, ok := selector.X.(*ast.Ident); ok && x.Name == "suite" && selector.Sel.Name == "Run" {
+			return call.Args[1]
 		}
 	}
-	return "", ""
+	return nil
 }
 
-func findExpressionName(exp ast.Expr) (pkgName, name string) {
+// findExpressionName returns a name of suite type of expression, or a name of function constructing the suite
+func findExpressionName(exp ast.Expr) (pkgName, name string, constructor bool) {
 	switch v := exp.(type) {
 	case *ast.CallExpr:
-		if _, funName := findExpressionName(v.Fun); funName == "new" {
+		fun := v.Fun
+		if index, ok := fun.(*ast.IndexExpr); ok {
+			// Generic constructor instantiation.
+			fun = index.X
+		}
+		pkgName, funName, _ := findExpressionName(fun)
+		if pkgName == "" && funName == "new" && len(v.Args) == 1 {
 			return findExpressionName(v.Args[0])
 		}
+		return pkgName, funName, funName != ""
 	case *ast.CompositeLit:
 		return findExpressionName(v.Type)
 	case *ast.UnaryExpr:
 		return findExpressionName(v.X)
+	case *ast.IndexExpr:
+		return findExpressionName(v.X)
 	case *ast.SelectorExpr:
 		if x, ok := v.X.(*ast.Ident); ok {
-			return x.Name, v.Sel.Name
+			return x.Name, v.Sel.Name, false
 		}
 	case *ast.Ident:
 		if v.Obj == nil {
-			return "", v.Name
+			return "", v.Name, false
 		}
 		if spec, ok := v.Obj.Decl.(*ast.ValueSpec); ok {
 			for _, val := range spec.Values {
-				pkgName, name = findExpressionName(val)
+				pkgName, name, constructor = findExpressionName(val)
 				if name != "" {
-					return pkgName, name
+					return pkgName, name, constructor
 				}
 			}
+			if spec.Type != nil {
+				return findExpressionName(spec.Type)
+			}
 		}
 		if assign, ok := v.Obj.Decl.(*ast.AssignStmt); ok {
 			for _, r := range assign.Rhs {
-				pkgName, name = findExpressionName(r.(ast.Expr))
+				pkgName, name, constructor = findExpressionName(r)
 				if name != "" {
-					return pkgName, name
+					return pkgName, name, constructor
 				}
 			}
 		}
-		return "", v.Name
+		return "", v.Name, false
+	case *ast.StarExpr:
+		return findExpressionName(v.X)
 	}
-	return "", ""
+	return "", "", false
 }
@@ -44,7 +44,7 @@ func testResults() map[string][]string {
 }
 
 func TestFind(t *testing.T) {
-	foundSuites, err := suites.Find("./samples")
+	foundSuites, err := suites.Find("./samples", nil)
 	require.NoError(t, err)
 
 	foundSuitesMap := make(map[string][]string)
@@ -55,3 +55,36 @@ func TestFind(t *testing.T) {
 
 	require.Equal(t, testResults(), foundSuitesMap)
 }
+
+func TestFindWithTags(t *testing.T) {
+	foundSuites, err := suites.Find("./samples", []string{"sample_tag"})
+	require.NoError(t, err)
+
+	foundSuitesMap := make(map[string][]string)
+	for _, suite := range foundSuites {
+		sort.Strings(suite.Tests)
+		foundSuitesMap[suite.Name] = suite.Tests
+	}
+
+	expected := testResults()
+	expected["TestTaggedEntryPoint"] = []string{"Test1", "Test2", "Test3"}
+	require.Equal(t, expected, foundSuitesMap)
+}
+
+func TestFindPackage(t *testing.T) {
+	foundSuites, err := suites.FindPackage("./samples/samples/nested", nil)
+	require.NoError(t, err)
+
+	var names []string
+	for _, suite := range foundSuites {
+		names = append(names, suite.Name)
+	}
+	require.Equal(t, []string{"TestEntryPoint8"}, names)
+
+	// Suites of nested packages are not found.
+	foundSuites, err = suites.FindPackage("./samples/samples", nil)
+	require.NoError(t, err)
+	for _, suite := range foundSuites {
+		require.NotEqual(t, "TestEntryPoint8", suite.Name)
+	}
+}
@@ -27,21 +27,21 @@ import (
 
 // File is an *ast.File with suites lookup
 type File struct {
-	resolvedImports map[string]*Package // import path -> *Package
-	imports         map[string]*Package // name -> *Package
-	suites          map[string]*Suite   // name -> *Suite
-	pkg             *Package
+	loader  *Loader
+	imports map[string]*Package // name -> *Package
+	suites  map[string]*Suite   // name -> *Suite
+	pkg     *Package
 
 	*ast.File
 }
 
-func newFile(file *ast.File, resolvedImports map[string]*Package, suites map[string]*Suite, pkg *Package) *File {
+func newFile(file *ast.File, loader *Loader, suites map[string]*Suite, pkg *Package) *File {
 	f := &File{
-		resolvedImports: resolvedImports,
-		imports:         make(map[string]*Package),
-		suites:          suites,
-		pkg:             pkg,
-		File:            file,
+		loader:  loader,
+		imports: make(map[string]*Package),
+		suites:  suites,
+		pkg:     pkg,
+		File:    file,
 	}
 
 	f.findImports()
@@ -61,10 +61,11 @@ func (f *File) findImports() {
 		}
 
 		var pkg *Package
-		if resolved, ok := f.resolvedImports[importPath]; ok {
+		if resolved, ok := f.loader.resolvedImports[importPath]; ok {
 			pkg = resolved
 		} else {
-			pkg = newImport(importPath, f.resolvedImports)
+			pkg = newImport(importPath, f.loader)
+			f.loader.resolvedImports[importPath] = pkg
 		}
 
 		f.imports[name] = pkg
@@ -80,6 +81,30 @@ func (f *File) Lookup(pkgName, name string) (*Suite, error) {
 	return pkg.Lookup(name)
 }
 
+// LookupConstructor looks up for a suite returned by `pkgName.name` function
+func (f *File) LookupConstructor(pkgName, name string) (*Suite, error) {
+	pkg, ok := f.imports[pkgName]
+	if !ok {
+		return nil, errors.Errorf("invalid import: %s", pkgName)
+	}
+	return pkg.LookupConstructor(name)
+}
+
+// findFuncResult returns a type of the first result of `name` function, or nil if there is no such function
+func (f *File) findFuncResult(name string) ast.Expr {
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || funcDecl.Name.Name != name {
+			continue
+		}
+		if funcDecl.Type.Results == nil || len(funcDecl.Type.Results.List) == 0 {
+			return nil
+		}
+		return funcDecl.Type.Results.List[0].Type
+	}
+	return nil
+}
+
 func (f *File) findSuiteParent(name string) (parentSuite *Suite, err error) {
 	for _, decl := range f.Decls {
 		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
@@ -112,9 +137,12 @@ func (f *File) getSuite(expr ast.Expr) (*Suite, error) {
 		return f.pkg.Lookup(v.Name)
 	case *ast.StarExpr:
 		return f.getSuite(v.X)
+	case *ast.IndexExpr:
+		// Generic suite type instantiation.
+		return f.getSuite(v.X)
 	case *ast.SelectorExpr:
 		if x, ok := v.X.(*ast.Ident); ok {
-			return f.imports[x.Name].Lookup(v.Sel.Name)
+			return f.Lookup(x.Name, v.Sel.Name)
 		}
 	}
 	return nil, nil
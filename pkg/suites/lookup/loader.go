@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+// Loader loads imported packages with go/packages, so packages are resolved in module mode the same way go build
+// does: with vendor folder and replaced modules
+type Loader struct {
+	dir             string
+	tags            []string
+	fileSet         *token.FileSet
+	resolvedImports map[string]*Package // import path -> *Package
+}
+
+// NewLoader creates a new Loader resolving packages from dir with build tags
+func NewLoader(dir string, tags []string) *Loader {
+	return &Loader{
+		dir:             dir,
+		tags:            tags,
+		fileSet:         token.NewFileSet(),
+		resolvedImports: ResolvedImports(),
+	}
+}
+
+// MatchFile reports whether file name in dir matches build tags of Loader
+func (l *Loader) MatchFile(dir, name string) bool {
+	ctx := build.Default
+	ctx.BuildTags = l.tags
+	match, err := ctx.MatchFile(dir, name)
+	return err == nil && match
+}
+
+func (l *Loader) load(importPath string) ([]*ast.File, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles,
+		Dir:  l.dir,
+	}
+	if len(l.tags) > 0 {
+		cfg.BuildFlags = []string{"-tags", strings.Join(l.tags, ",")}
+	}
+
+	pkgs, err := packages.Load(cfg, importPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load package: %s", importPath)
+	}
+	if len(pkgs) != 1 {
+		return nil, errors.Errorf("found %d packages by import path: %s", len(pkgs), importPath)
+	}
+	if len(pkgs[0].Errors) > 0 {
+		return nil, errors.Errorf("failed to load package: %s: %v", importPath, pkgs[0].Errors[0])
+	}
+
+	var files []*ast.File
+	for _, fileName := range pkgs[0].GoFiles {
+		file, err := parser.ParseFile(l.fileSet, fileName, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
@@ -18,83 +18,68 @@ package lookup
 
 import (
 	"go/ast"
-	"go/build"
-	"go/parser"
-	"go/token"
-	"os"
-	"strings"
 	"sync"
-
-	"github.com/pkg/errors"
 )
 
 // Package is an *ast.Package with suites lookup
 type Package struct {
-	resolvedImports map[string]*Package // import path -> *Package
-	suites          map[string]*Suite   // name -> *Suite
-	importPath      string
-	Files           []*File
-	once            *sync.Once
-	resolveErr      error
+	loader     *Loader
+	suites     map[string]*Suite // name -> *Suite
+	importPath string
+	Files      []*File
+	once       *sync.Once
+	resolveErr error
 }
 
 // NewPackage creates a new Package from *ast.Package
-func NewPackage(pkg *ast.Package, resolvedImports map[string]*Package) *Package {
+func NewPackage(pkg *ast.Package, loader *Loader) *Package {
 	p := &Package{
-		resolvedImports: resolvedImports,
-		suites:          make(map[string]*Suite),
+		loader: loader,
+		suites: make(map[string]*Suite),
 	}
 
 	for _, f := range pkg.Files {
-		p.Files = append(p.Files, newFile(f, p.resolvedImports, p.suites, p))
+		p.Files = append(p.Files, newFile(f, p.loader, p.suites, p))
 	}
 
 	return p
 }
 
-func newImport(importPath string, resolvedImports map[string]*Package) *Package {
+func newImport(importPath string, loader *Loader) *Package {
 	return &Package{
-		resolvedImports: resolvedImports,
-		suites:          make(map[string]*Suite),
-		importPath:      importPath,
-		once:            new(sync.Once),
+		loader:     loader,
+		suites:     make(map[string]*Suite),
+		importPath: importPath,
+		once:       new(sync.Once),
 	}
 }
 
 func (p *Package) resolve() error {
-	pkg, err := build.Default.Import(p.importPath, ".", build.FindOnly)
+	files, err := p.loader.load(p.importPath)
 	if err != nil {
 		return err
 	}
 
-	pkgNodes, err := parser.ParseDir(token.NewFileSet(), pkg.Dir, func(fileInfo os.FileInfo) bool {
-		return !strings.HasSuffix(fileInfo.Name(), "_test.go")
-	}, 0)
-	if err != nil {
-		return err
-	}
-	if len(pkgNodes) != 1 {
-		return errors.Errorf("found more than 1 package in directory: %s", p.importPath)
-	}
-
-	for _, pkgNode := range pkgNodes {
-		for _, f := range pkgNode.Files {
-			p.Files = append(p.Files, newFile(f, p.resolvedImports, p.suites, p))
-		}
+	for _, f := range files {
+		p.Files = append(p.Files, newFile(f, p.loader, p.suites, p))
 	}
 
 	return nil
 }
 
-// Lookup looks up for a `name` suite
-func (p *Package) Lookup(name string) (suite *Suite, err error) {
+func (p *Package) ensureResolved() error {
 	if p.once != nil {
 		p.once.Do(func() {
 			p.resolveErr = p.resolve()
 		})
 	}
-	if p.resolveErr != nil {
-		return nil, p.resolveErr
+	return p.resolveErr
+}
+
+// Lookup looks up for a `name` suite
+func (p *Package) Lookup(name string) (suite *Suite, err error) {
+	if err = p.ensureResolved(); err != nil {
+		return nil, err
 	}
 
 	var ok bool
@@ -114,6 +99,19 @@ func (p *Package) Lookup(name string) (suite *Suite, err error) {
 	return suite, nil
 }
 
+// LookupConstructor looks up for a suite returned by `name` function
+func (p *Package) LookupConstructor(name string) (*Suite, error) {
+	if err := p.ensureResolved(); err != nil {
+		return nil, err
+	}
+
+	for _, file := range p.Files {
+		if resultType := file.findFuncResult(name); resultType != nil {
+			return file.getSuite(resultType)
+		}
+	}
+	return nil, nil
+}
 func (p *Package) findSuiteParent(name string) (parentSuite *Suite, err error) {
 	for i := 0; parentSuite == nil && i < len(p.Files); i++ {
 		if parentSuite, err = p.Files[i].findSuiteParent(name); err != nil {
@@ -41,6 +41,14 @@ func (e *TestEvent) TestName() string {
 	return ""
 }
 
+// SubtestName returns TestEvent test name without suite name, including names of nested tests: "Test/nested"
+func (e *TestEvent) SubtestName() string {
+	if split := strings.SplitN(e.Test, "/", 2); len(split) > 1 {
+		return split[1]
+	}
+	return ""
+}
+
 // Process calls corresponding TestEventProcessor Process* method
 func (e *TestEvent) Process(processor TestEventProcessor) error {
 	switch e.Action {
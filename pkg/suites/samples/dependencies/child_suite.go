@@ -33,3 +33,9 @@ func (s *LibSuite) Test2() {
 // Test3 comment
 func (s *LibSuite) Test3() {
 }
+
+// NewLibSuite comment
+func NewLibSuite(name string) *LibSuite {
+	_ = name
+	return &LibSuite{}
+}
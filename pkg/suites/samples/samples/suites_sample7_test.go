@@ -0,0 +1,57 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package samples
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/networkservicemesh/cloudtest/pkg/suites/samples/dependencies"
+)
+
+type ConstructedSuite struct {
+	suite.Suite
+	name string
+}
+
+func (s *ConstructedSuite) Test1() {
+}
+func (s *ConstructedSuite) Test2() {
+}
+
+func newConstructedSuite(name string) *ConstructedSuite {
+	return &ConstructedSuite{name: name}
+}
+
+func TestConstructorEntryPoint1(t *testing.T) {
+	suite.Run(t, newConstructedSuite("sample"))
+}
+
+func TestConstructorEntryPoint2(t *testing.T) {
+	s := newConstructedSuite("sample")
+	suite.Run(t, s)
+}
+
+func TestLibConstructorEntryPoint(t *testing.T) {
+	suite.Run(t, dependencies.NewLibSuite("sample"))
+}
+
+func TestUnresolvedEntryPoint(t *testing.T) {
+	suites := []suite.TestingSuite{new(Suite1)}
+	suite.Run(t, suites[0])
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package subtests comment
+package subtests
+
+import "testing"
+
+type sample struct {
+	name  string
+	value int
+}
+
+// TestLiterals comment
+func TestLiterals(t *testing.T) {
+	t.Run("first", func(t *testing.T) {})
+	t.Run("second case", func(t *testing.T) {
+		t.Run("nested", func(t *testing.T) {})
+	})
+}
+
+// TestTable comment
+func TestTable(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value int
+	}{
+		{name: "one", value: 1},
+		{name: "two", value: 2},
+	} {
+		value := tc.value
+		t.Run(tc.name, func(t *testing.T) {
+			_ = value
+		})
+	}
+}
+
+// TestNamedTable comment
+func TestNamedTable(t *testing.T) {
+	samples := []sample{
+		{"one", 1},
+		{"two", 2},
+	}
+	for _, s := range samples {
+		t.Run(s.name, func(t *testing.T) {})
+	}
+}
+
+// TestMap comment
+func TestMap(t *testing.T) {
+	for name := range map[string]int{
+		"a": 1,
+		"b": 2,
+	} {
+		t.Run(name, func(t *testing.T) {})
+	}
+}
+
+// TestDynamic comment
+func TestDynamic(t *testing.T) {
+	for _, name := range []string{"a", "b"} {
+		t.Run(name+"-dynamic", func(t *testing.T) {})
+	}
+}
+
+// TestDuplicate comment
+func TestDuplicate(t *testing.T) {
+	t.Run("same", func(t *testing.T) {})
+	t.Run("same", func(t *testing.T) {})
+}
+
+// TestPlain comment
+func TestPlain(t *testing.T) {
+}
@@ -27,7 +27,8 @@ import (
 )
 
 const (
-	setupSuite = "SetupSuite"
+	setupSuite    = "SetupSuite"
+	tearDownSuite = "TearDownSuite"
 )
 
 // SkipSuite returns list of model.TestEntry for the skipped go suite
@@ -46,7 +47,8 @@ func SkipSuite(suite *model.TestEntry) (tests []*model.TestEntry) {
 	return tests
 }
 
-// SplitSuite returns list of model.TestEntry for the passed/failed go suite
+// SplitSuite returns list of model.TestEntry for the passed/failed go suite: an entry for every suite method and its
+// nested subtests. Suite level failure not caused by any method is returned as SetupSuite or TearDownSuite entry
 func SplitSuite(
 	suite *model.TestEntry,
 	manager execmanager.ExecutionManager,
@@ -56,33 +58,65 @@ func SplitSuite(
 	suiteTests[0] = setupSuite
 	copy(suiteTests[1:], suite.Suite.Tests)
 
-	builders := make(map[string]*testentry.Builder)
+	builders := &suiteBuilders{
+		suite:         suite,
+		manager:       manager,
+		clusterTaskID: clusterTaskID,
+		builders:      make(map[string]*testentry.Builder),
+	}
 	for _, testName := range suiteTests {
-		builders[testName] = testentry.NewBuilder(testName, suite, manager, clusterTaskID)
+		builders.add(testName)
 	}
 
-	if err = splitExecutions(suite, builders); err != nil {
+	if err = splitExecutions(suite, suiteTests, builders); err != nil {
 		return nil, err
 	}
 
-	setup := builders[setupSuite].Build()
-	delete(builders, setupSuite)
+	setup := builders.builders[setupSuite].Build()
 
-	allSkip := true
-	for _, builder := range builders {
-		testEntry := builder.Build()
+	allSkip, anyFailed := true, false
+	for _, testName := range builders.names[1:] {
+		testEntry := builders.builders[testName].Build()
 		allSkip = allSkip && (testEntry.Status == model.StatusSkipped)
-		tests = append(tests, builder.Build())
+		anyFailed = anyFailed || testEntry.Status == model.StatusFailed || testEntry.Status == model.StatusTimeout
+		tests = append(tests, testEntry)
 	}
 
-	if allSkip && setup.Status == model.StatusFailed {
-		tests = append([]*model.TestEntry{setup}, tests...)
+	if (setup.Status == model.StatusFailed || setup.Status == model.StatusTimeout) && !anyFailed {
+		// Suite is failed, but none of methods is, so it is failed in SetupSuite or TearDownSuite.
+		if allSkip {
+			tests = append([]*model.TestEntry{setup}, tests...)
+		} else {
+			setup.Name = tearDownSuite
+			tests = append(tests, setup)
+		}
 	}
 
 	return tests, nil
 }
 
-func splitExecutions(suite *model.TestEntry, builders map[string]*testentry.Builder) error {
+// IsNestedTest returns true if test entry of SplitSuite is a nested subtest of suite method
+func IsNestedTest(test *model.TestEntry) bool {
+	return strings.Contains(test.Name, "/")
+}
+
+// suiteBuilders - builders of suite entries, builders of nested subtests are added on their first event.
+type suiteBuilders struct {
+	suite         *model.TestEntry
+	manager       execmanager.ExecutionManager
+	clusterTaskID string
+	builders      map[string]*testentry.Builder
+	names         []string // Names of entries in order of adding.
+}
+
+func (b *suiteBuilders) add(testName string) *testentry.Builder {
+	builder := testentry.NewBuilder(testName, b.suite, b.manager, b.clusterTaskID)
+	b.builders[testName] = builder
+	b.names = append(b.names, testName)
+	return builder
+}
+
+func splitExecutions(suite *model.TestEntry, suiteTests []string, builders *suiteBuilders) error {
 	for _, execution := range suite.Executions {
 		file, err := os.Open(execution.OutputFile)
 		if err != nil {
@@ -103,14 +137,32 @@ func splitExecutions(suite *model.TestEntry, builders map[string]*testentry.Buil
 				testName = event.TestName()
 			}
 
-			if err = event.Process(builders[testName]); err != nil {
-				return err
+			builder, ok := builders.builders[testName]
+			if !ok {
+				// A test not requested by run pattern.
+				continue
 			}
 			run[testName] = struct{}{}
+
+			if subtestName := event.SubtestName(); subtestName != testName && testName != setupSuite {
+				// Nested subtest output is kept in method output too.
+				if event.Action == "output" {
+					if err = event.Process(builder); err != nil {
+						return err
+					}
+				}
+				if builder, ok = builders.builders[subtestName]; !ok {
+					builder = builders.add(subtestName)
+				}
+			}
+			if err = event.Process(builder); err != nil {
+				return err
+			}
 		}
 
-		for testName, builder := range builders {
+		for _, testName := range suiteTests {
 			if _, ok := run[testName]; !ok {
+				builder := builders.builders[testName]
 				if err = builder.ProcessRunEvent(&parse.TestEvent{Time: suite.Started}); err != nil {
 					return err
 				}
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suites_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/model"
+	"github.com/networkservicemesh/cloudtest/pkg/suites"
+	"github.com/networkservicemesh/cloudtest/pkg/suites/parse"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+const suiteName = "TestSuite"
+
+func splitSuite(t *testing.T, events []*parse.TestEvent) map[string]*model.TestEntry {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "split-suite")
+	require.NoError(t, err)
+	t.Cleanup(func() { utils.ClearFolder(tmpDir, false) })
+
+	output := strings.Builder{}
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		require.NoError(t, err)
+		_, _ = output.Write(line)
+		_ = output.WriteByte('\n')
+	}
+	outputFile := path.Join(tmpDir, "output.log")
+	require.NoError(t, ioutil.WriteFile(outputFile, []byte(output.String()), os.ModePerm))
+
+	suite := &model.TestEntry{
+		Name:            suiteName,
+		Kind:            model.SuiteTestKind,
+		Suite:           &model.Suite{Name: suiteName, Tests: []string{"TestA", "TestB"}},
+		ExecutionConfig: &config.Execution{Name: "split"},
+		Executions:      []model.TestEntryExecution{{OutputFile: outputFile, Retry: 1}},
+	}
+	tests, err := suites.SplitSuite(suite, execmanager.NewExecutionManager(path.Join(tmpDir, "results")), "a_provider")
+	require.NoError(t, err)
+
+	result := map[string]*model.TestEntry{}
+	for _, test := range tests {
+		result[test.Name] = test
+	}
+	return result
+}
+
+func suiteEvent(action, test, output string, elapsed float64) *parse.TestEvent {
+	if test != "" {
+		test = suiteName + "/" + test
+	} else {
+		test = suiteName
+	}
+	return &parse.TestEvent{Time: time.Now(), Action: action, Test: test, Output: output, Elapsed: elapsed}
+}
+
+func TestSplitSuiteNestedTests(t *testing.T) {
+	tests := splitSuite(t, []*parse.TestEvent{
+		suiteEvent("run", "", "", 0),
+		suiteEvent("run", "TestA", "", 0),
+		suiteEvent("run", "TestA/case_1", "", 0),
+		suiteEvent("output", "TestA/case_1", "case 1 output\n", 0),
+		suiteEvent("pass", "TestA/case_1", "", 0.5),
+		suiteEvent("run", "TestA/case_2", "", 0),
+		suiteEvent("output", "TestA/case_2", "case 2 output\n", 0),
+		suiteEvent("fail", "TestA/case_2", "", 1.5),
+		suiteEvent("output", "TestA", "method output\n", 0),
+		suiteEvent("fail", "TestA", "", 2),
+		suiteEvent("run", "TestB", "", 0),
+		suiteEvent("pass", "TestB", "", 3),
+		suiteEvent("fail", "", "", 5),
+	})
+
+	require.Len(t, tests, 4)
+	require.Equal(t, model.StatusFailed, tests["TestA"].Status)
+	require.Equal(t, 2*time.Second, tests["TestA"].Duration)
+	require.Equal(t, model.StatusSuccess, tests["TestA/case_1"].Status)
+	require.Equal(t, 500*time.Millisecond, tests["TestA/case_1"].Duration)
+	require.Equal(t, model.StatusFailed, tests["TestA/case_2"].Status)
+	require.Equal(t, model.StatusSuccess, tests["TestB"].Status)
+	require.True(t, suites.IsNestedTest(tests["TestA/case_2"]))
+	require.False(t, suites.IsNestedTest(tests["TestA"]))
+
+	lines, err := utils.ReadFile(tests["TestA"].Executions[0].OutputFile)
+	require.NoError(t, err)
+	require.Equal(t, []string{"case 1 output", "case 2 output", "method output"}, lines)
+
+	lines, err = utils.ReadFile(tests["TestA/case_2"].Executions[0].OutputFile)
+	require.NoError(t, err)
+	require.Equal(t, []string{"case 2 output"}, lines)
+}
+
+func TestSplitSuiteTearDownFailure(t *testing.T) {
+	tests := splitSuite(t, []*parse.TestEvent{
+		suiteEvent("run", "", "", 0),
+		suiteEvent("run", "TestA", "", 0),
+		suiteEvent("pass", "TestA", "", 1),
+		suiteEvent("run", "TestB", "", 0),
+		suiteEvent("pass", "TestB", "", 1),
+		suiteEvent("output", "", "teardown failed\n", 0),
+		suiteEvent("fail", "", "", 3),
+	})
+
+	require.Len(t, tests, 3)
+	require.Equal(t, model.StatusSuccess, tests["TestA"].Status)
+	require.Equal(t, model.StatusSuccess, tests["TestB"].Status)
+	require.Equal(t, model.StatusFailed, tests["TearDownSuite"].Status)
+}
+
+func TestSplitSuiteSetupFailure(t *testing.T) {
+	tests := splitSuite(t, []*parse.TestEvent{
+		suiteEvent("run", "", "", 0),
+		suiteEvent("output", "", "setup failed\n", 0),
+		suiteEvent("fail", "", "", 1),
+	})
+
+	require.Len(t, tests, 3)
+	require.Equal(t, model.StatusSkipped, tests["TestA"].Status)
+	require.Equal(t, model.StatusSkipped, tests["TestB"].Status)
+	require.Equal(t, model.StatusFailed, tests["SetupSuite"].Status)
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suites
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FindSubtests finds subtests of go tests in package dir. Subtests are found by static analysis, every t.Run of test
+// should use a string literal name, or a name field of table defined by composite literal the test ranges over.
+// Tests with subtests could not be resolved this way, with duplicate or nested names are not returned.
+func FindSubtests(dir string) (map[string][]string, error) {
+	fileSet := token.NewFileSet()
+	pkgNodes, err := parser.ParseDir(fileSet, dir, func(info os.FileInfo) bool {
+		return strings.HasSuffix(info.Name(), "_test.go")
+	}, noneParseFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string][]string{}
+	for _, pkgNode := range pkgNodes {
+		for _, file := range pkgNode.Files {
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || !isTestFunc(funcDecl) || len(funcDecl.Type.Params.List[0].Names) == 0 {
+					continue
+				}
+				finder := &subtestFinder{
+					t:    funcDecl.Type.Params.List[0].Names[0].Name,
+					seen: map[string]bool{},
+				}
+				finder.walk(funcDecl.Body, nil)
+				if finder.resolved && len(finder.names) > 0 {
+					result[funcDecl.Name.Name] = finder.names
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+type subtestFinder struct {
+	t        string // A name of *testing.T parameter.
+	names    []string
+	seen     map[string]bool
+	resolved bool
+	failed   bool
+}
+
+func (f *subtestFinder) walk(node ast.Node, ranges []*ast.RangeStmt) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if f.failed {
+			return false
+		}
+		switch v := n.(type) {
+		case *ast.RangeStmt:
+			f.walk(v.Body, append(append([]*ast.RangeStmt{}, ranges...), v))
+			return false
+		case *ast.CallExpr:
+			if f.isRunCall(v) {
+				f.add(f.resolveNames(v.Args[0], ranges))
+				// Nested subtests are not split.
+				return false
+			}
+		}
+		return true
+	})
+	f.resolved = !f.failed
+}
+
+func (f *subtestFinder) isRunCall(call *ast.CallExpr) bool {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Run" || len(call.Args) != 2 {
+		return false
+	}
+	x, ok := selector.X.(*ast.Ident)
+	return ok && x.Name == f.t
+}
+
+func (f *subtestFinder) add(names []string, ok bool) {
+	if !ok {
+		f.failed = true
+		return
+	}
+	for _, name := range names {
+		name = subtestName(name)
+		if name == "" || strings.Contains(name, "/") || f.seen[name] {
+			// Go test adds suffixes to duplicate names and splits names by slash.
+			f.failed = true
+			return
+		}
+		f.seen[name] = true
+		f.names = append(f.names, name)
+	}
+}
+
+// resolveNames - return all values of subtest name expression.
+func (f *subtestFinder) resolveNames(expr ast.Expr, ranges []*ast.RangeStmt) ([]string, bool) {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		name, ok := stringValue(v)
+		return []string{name}, ok
+	case *ast.Ident:
+		// for name := range map[string]...{...}
+		for i := len(ranges) - 1; i >= 0; i-- {
+			if isIdent(ranges[i].Key, v.Name) {
+				table := compositeLit(ranges[i].X)
+				if table == nil {
+					return nil, false
+				}
+				if _, ok := table.Type.(*ast.MapType); !ok {
+					return nil, false
+				}
+				return tableValues(table, func(kv *ast.KeyValueExpr, _ ast.Expr, _ int) ast.Expr { return kv.Key })
+			}
+		}
+	case *ast.SelectorExpr:
+		// for _, tc := range []struct{name string}{...}
+		x, ok := v.X.(*ast.Ident)
+		if !ok {
+			return nil, false
+		}
+		for i := len(ranges) - 1; i >= 0; i-- {
+			if isIdent(ranges[i].Value, x.Name) {
+				return fieldValues(compositeLit(ranges[i].X), v.Sel.Name)
+			}
+		}
+	}
+	return nil, false
+}
+
+// fieldValues - return values of struct field of every table row.
+func fieldValues(table *ast.CompositeLit, field string) ([]string, bool) {
+	if table == nil {
+		return nil, false
+	}
+	var elementType ast.Expr
+	switch t := table.Type.(type) {
+	case *ast.ArrayType:
+		elementType = t.Elt
+	case *ast.MapType:
+		elementType = t.Value
+	default:
+		return nil, false
+	}
+	index := fieldIndex(elementType, field)
+	return tableValues(table, func(_ *ast.KeyValueExpr, row ast.Expr, _ int) ast.Expr {
+		lit, ok := row.(*ast.CompositeLit)
+		if !ok {
+			return nil
+		}
+		for i, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if isIdent(kv.Key, field) {
+					return kv.Value
+				}
+			} else if i == index {
+				return elt
+			}
+		}
+		return nil
+	})
+}
+
+// tableValues - return string values selected from every table row.
+func tableValues(table *ast.CompositeLit, selector func(kv *ast.KeyValueExpr, row ast.Expr, index int) ast.Expr) ([]string, bool) {
+	var result []string
+	for i, elt := range table.Elts {
+		kv, _ := elt.(*ast.KeyValueExpr)
+		row := elt
+		if kv != nil {
+			row = kv.Value
+		}
+		lit, ok := selector(kv, row, i).(*ast.BasicLit)
+		if !ok {
+			return nil, false
+		}
+		value, ok := stringValue(lit)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, value)
+	}
+	return result, true
+}
+
+// fieldIndex - return an index of field in struct type, or -1 if it could not be found.
+func fieldIndex(expr ast.Expr, field string) int {
+	if ident, ok := expr.(*ast.Ident); ok && ident.Obj != nil {
+		if spec, ok := ident.Obj.Decl.(*ast.TypeSpec); ok {
+			expr = spec.Type
+		}
+	}
+	structType, ok := expr.(*ast.StructType)
+	if !ok {
+		return -1
+	}
+	index := 0
+	for _, f := range structType.Fields.List {
+		if len(f.Names) == 0 {
+			index++
+			continue
+		}
+		for _, name := range f.Names {
+			if name.Name == field {
+				return index
+			}
+			index++
+		}
+	}
+	return -1
+}
+
+// compositeLit - return a composite literal expression is defined by.
+func compositeLit(expr ast.Expr) *ast.CompositeLit {
+	switch v := expr.(type) {
+	case *ast.CompositeLit:
+		return v
+	case *ast.Ident:
+		if v.Obj == nil {
+			return nil
+		}
+		switch decl := v.Obj.Decl.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range decl.Lhs {
+				if isIdent(lhs, v.Name) && i < len(decl.Rhs) {
+					return compositeLit(decl.Rhs[i])
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range decl.Names {
+				if name.Name == v.Name && i < len(decl.Values) {
+					return compositeLit(decl.Values[i])
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+func stringValue(lit *ast.BasicLit) (string, bool) {
+	if lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// subtestName - return a name of subtest the same way go test rewrites it.
+func subtestName(name string) string {
+	result := strings.Builder{}
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r):
+			_, _ = result.WriteRune('_')
+		case !strconv.IsPrint(r):
+			s := strconv.QuoteRune(r)
+			_, _ = result.WriteString(s[1 : len(s)-1])
+		default:
+			_, _ = result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
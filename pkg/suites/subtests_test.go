@@ -0,0 +1,37 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package suites_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/suites"
+)
+
+func TestFindSubtests(t *testing.T) {
+	subtests, err := suites.FindSubtests("./samples/subtests")
+	require.NoError(t, err)
+
+	require.Equal(t, map[string][]string{
+		"TestLiterals":   {"first", "second_case"},
+		"TestTable":      {"one", "two"},
+		"TestNamedTable": {"one", "two"},
+		"TestMap":        {"a", "b"},
+	}, subtests)
+}
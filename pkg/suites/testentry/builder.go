@@ -20,6 +20,8 @@ package testentry
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
 	"github.com/networkservicemesh/cloudtest/pkg/model"
@@ -71,7 +73,7 @@ func (b *Builder) ProcessRunEvent(testEvent *parse.TestEvent) (err error) {
 		b.testEntry.Started = testEvent.Time
 	}
 
-	fileName := fmt.Sprintf("%s-%s", b.suiteEntry.Name, b.testEntry.Name)
+	fileName := strings.ReplaceAll(fmt.Sprintf("%s-%s", b.suiteEntry.Name, b.testEntry.Name), "/", "-")
 	if fileName, b.file, err = b.manager.OpenFileTest(b.clusterTaskID, fileName, "run"); err != nil {
 		return err
 	}
@@ -112,6 +114,10 @@ func (b *Builder) ProcessSkipEvent(testEvent *parse.TestEvent) error {
 
 func (b *Builder) processStatusEvent(testEvent *parse.TestEvent, status model.Status) error {
 	b.testEntry.Duration = testEvent.Time.Sub(b.testEntry.Started)
+	if testEvent.Elapsed > 0 {
+		// test2json reports elapsed time measured by the test itself.
+		b.testEntry.Duration = time.Duration(testEvent.Elapsed * float64(time.Second))
+	}
 
 	b.testEntry.Executions[len(b.testEntry.Executions)-1].Status = status
 	b.testEntry.Status = status
@@ -0,0 +1,217 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/commands"
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/reporting"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func findSuite(suites []*reporting.Suite, name string) *reporting.Suite {
+	for _, s := range suites {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestClusterRequirements(t *testing.T) {
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	createProvider(testConfig, "a_provider").Labels = map[string]string{"nodes": "3", "sriov": ""}
+	createProvider(testConfig, "b_provider").Labels = map[string]string{"nodes": "1"}
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "big",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tags: []string{"passed"},
+		},
+		OnlyRun: []string{"TestPass1", "TestPass2"},
+		ClusterRequirements: []*config.ClusterRequirement{
+			{Key: "nodes", Operator: config.OperatorIn, Values: []string{"3", "5"}},
+			{Key: "sriov", Operator: config.OperatorExists},
+		},
+	}, &config.Execution{
+		Name:        "ipv6",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tags: []string{"passed"},
+		},
+		OnlyRun: []string{"TestPass3"},
+		ClusterRequirements: []*config.ClusterRequirement{
+			{Key: "ipv6", Operator: config.OperatorExists},
+		},
+	})
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	rootSuite := report.Suites[0]
+	require.Equal(t, 3, rootSuite.Tests)
+	require.Equal(t, 0, rootSuite.Failures)
+
+	big := findSuite(rootSuite.Suites, "big")
+	require.NotNil(t, big)
+	require.Len(t, big.Suites, 1)
+	require.Equal(t, "a_provider", big.Suites[0].Name)
+	require.Equal(t, 2, big.Tests)
+
+	ipv6 := findSuite(rootSuite.Suites, "ipv6")
+	require.NotNil(t, ipv6)
+	require.Len(t, ipv6.Suites, 1)
+	require.Len(t, ipv6.Suites[0].TestCases, 1)
+	require.NotNil(t, ipv6.Suites[0].TestCases[0].SkipMessage)
+	require.Equal(t, "No enabled cluster satisfies requirements: ipv6 Exists", ipv6.Suites[0].TestCases[0].SkipMessage.Message)
+
+	require.Equal(t, 1, logKeeper.MessageCount("No tests found for cluster config 'b_provider'"))
+}
+
+func TestClusterRequirementsAnyMatchingCluster(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	createProvider(testConfig, "a_provider").Labels = map[string]string{"nodes": "3"}
+	createProvider(testConfig, "b_provider").Labels = map[string]string{"nodes": "5"}
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "big",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tags: []string{"passed"},
+		},
+		OnlyRun: []string{"TestPass1", "TestPass2", "TestPass3"},
+		ClusterRequirements: []*config.ClusterRequirement{
+			{Key: "nodes", Operator: config.OperatorIn, Values: []string{"3", "5"}},
+		},
+	})
+
+	report, err := commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	// Every test is executed once on any of matching clusters.
+	big := findSuite(report.Suites[0].Suites, "big")
+	require.NotNil(t, big)
+	require.Equal(t, 3, big.Tests)
+	require.Equal(t, 0, big.Failures)
+
+	var names []string
+	for _, suite := range big.Suites {
+		require.Contains(t, []string{"a_provider", "b_provider"}, suite.Name)
+		for _, testCase := range suite.TestCases {
+			names = append(names, testCase.Name)
+		}
+	}
+	require.ElementsMatch(t, []string{"TestPass1", "TestPass2", "TestPass3"}, names)
+}
+
+func TestClusterRequirementsClusterCountWithoutSelector(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	createProvider(testConfig, "a_provider").Labels = map[string]string{"nodes": "3"}
+	createProvider(testConfig, "b_provider").Labels = map[string]string{"nodes": "3"}
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:         "multi",
+		Timeout:      15,
+		PackageRoot:  "./sample",
+		ClusterCount: 2,
+		Source: config.ExecutionSource{
+			Tags: []string{"passed"},
+		},
+		OnlyRun: []string{"TestPass1"},
+		ClusterRequirements: []*config.ClusterRequirement{
+			{Key: "nodes", Operator: config.OperatorIn, Values: []string{"3"}},
+		},
+	})
+
+	report, err := commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	multi := findSuite(report.Suites[0].Suites, "multi")
+	require.NotNil(t, multi)
+	require.Len(t, multi.Suites, 1)
+	require.Len(t, multi.Suites[0].TestCases, 1)
+	require.NotNil(t, multi.Suites[0].TestCases[0].SkipMessage)
+	require.Equal(t, "Cluster selector of 2 clusters is required to match requirements: nodes In (3)",
+		multi.Suites[0].TestCases[0].SkipMessage.Message)
+}
+
+func TestClusterRequirementsValidation(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	createProvider(testConfig, "a_provider")
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "simple",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tags: []string{"passed"},
+		},
+		OnlyRun: []string{"TestPass1"},
+		ClusterRequirements: []*config.ClusterRequirement{
+			{Key: "nodes", Operator: "Gt", Values: []string{"3"}},
+		},
+	})
+
+	_, err = commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown requirement operator Gt")
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/commands"
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/events"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestEventsFile(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = path.Join(tmpDir, "root")
+	testConfig.Events.File = path.Join(tmpDir, "events.ndjson")
+
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "simple",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tests: []string{"TestPass", "TestFail"},
+		},
+		OnFail: "echo on fail",
+	})
+
+	_, err = commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.Error(t, err)
+
+	lines, err := utils.ReadFile(testConfig.Events.File)
+	require.NoError(t, err)
+
+	var kindsOrder []events.Kind
+	kinds := map[events.Kind]int{}
+	finished := map[string]string{}
+	for _, line := range lines {
+		event := &events.Event{}
+		require.NoError(t, json.Unmarshal([]byte(line), event))
+		require.Equal(t, events.SchemaVersion, event.Version)
+		kindsOrder = append(kindsOrder, event.Kind)
+		kinds[event.Kind]++
+		if event.Kind == events.TaskFinished {
+			require.NotEmpty(t, event.TaskID)
+			require.Equal(t, "a_provider-1", event.ClusterInstance)
+			require.NotNil(t, event.Started)
+			finished[event.Test] = event.Status
+		}
+	}
+	require.Equal(t, events.RunStarted, kindsOrder[0])
+	require.Equal(t, events.RunFinished, kindsOrder[len(kindsOrder)-1])
+	require.Equal(t, 1, kinds[events.RunStarted])
+	require.Equal(t, 1, kinds[events.RunFinished])
+	require.Equal(t, 2, kinds[events.TaskStarted])
+	require.Equal(t, 1, kinds[events.OnFail])
+	require.NotZero(t, kinds[events.ClusterState])
+	require.Equal(t, map[string]string{"TestPass": "success", "TestFail": "failed"}, finished)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/commands"
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestHTMLReport(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "simple",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tests: []string{"TestPass", "TestFail"},
+		},
+	})
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+	testConfig.Reporting.HTMLReportFile = "reports/report.html"
+
+	_, err = commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.Error(t, err)
+
+	content, err := ioutil.ReadFile(filepath.Clean(path.Join(tmpDir, "reports", "report.html")))
+	require.NoError(t, err)
+	report := string(content)
+
+	require.Contains(t, report, "<td>TestPass</td>")
+	require.Contains(t, report, "<td>TestFail</td>")
+	require.Contains(t, report, `<td class="status success">success</td>`)
+	require.Contains(t, report, `<td class="status failed">failed</td>`)
+	// Cluster timeline has a start of instance and both tests.
+	require.Contains(t, report, "<td>a_provider-1</td>")
+	require.Contains(t, report, `class="span start"`)
+	require.Contains(t, report, `class="span success"`)
+	require.Contains(t, report, `class="span failed"`)
+	// Logs are linked relative to report folder.
+	require.Contains(t, report, `href="../a_provider-1/`)
+}
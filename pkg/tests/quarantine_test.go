@@ -0,0 +1,132 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/commands"
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/reporting"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestQuarantine(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = path.Join(tmpDir, "results")
+
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "simple",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tests: []string{"TestPass", "TestFail"},
+		},
+	})
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+	testConfig.FailedTestsLimit = 1
+	testConfig.Quarantine = []string{"simple/TestFail"}
+	testConfig.History.File = path.Join(tmpDir, "history.json")
+
+	report, err := commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Suites[0].Tests)
+	require.Equal(t, 0, report.Suites[0].Failures)
+
+	testCases := report.Suites[0].Suites[0].Suites[0].TestCases
+	require.Len(t, testCases, 2)
+	for _, tc := range testCases {
+		require.Equal(t, tc.Name == "TestFail", tc.Quarantined)
+		require.Equal(t, tc.Name == "TestFail", tc.Failure != nil)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Clean(testConfig.History.File))
+	require.NoError(t, err)
+	history := struct {
+		Tests map[string][]struct {
+			Status string `json:"status"`
+		} `json:"tests"`
+	}{}
+	require.NoError(t, json.Unmarshal(content, &history))
+	require.Len(t, history.Tests, 2)
+	require.Equal(t, "failed", history.Tests["simple/TestFail"][0].Status)
+	require.Equal(t, "success", history.Tests["simple/TestPass"][0].Status)
+}
+
+func TestQuarantineSplitSuite(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+	testConfig.MinSuiteSize = 1
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = path.Join(tmpDir, "results")
+
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 2
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "simple",
+		Timeout:     15,
+		PackageRoot: "./sample/suites",
+		Source: config.ExecutionSource{
+			Tests: []string{"TestRunSuiteExample"},
+		},
+	})
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+	testConfig.FailedTestsLimit = 1
+	testConfig.Quarantine = []string{"simple/TestRunSuiteExample"}
+
+	report, err := commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.NoError(t, err)
+	require.Equal(t, 0, report.Suites[0].Failures)
+
+	var testCases []*reporting.TestCase
+	var collect func(suite *reporting.Suite)
+	collect = func(suite *reporting.Suite) {
+		require.Equal(t, 0, suite.Failures)
+		testCases = append(testCases, suite.TestCases...)
+		for _, s := range suite.Suites {
+			collect(s)
+		}
+	}
+	collect(report.Suites[0])
+
+	require.Len(t, testCases, 2)
+	for _, tc := range testCases {
+		require.True(t, tc.Quarantined)
+		require.Equal(t, tc.Name == "TestFail", tc.Failure != nil)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/commands"
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestReporters(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "simple",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tests: []string{"TestPass", "TestFail"},
+		},
+	})
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+	testConfig.Reporting.Reporters = []*config.ReporterConfig{
+		{Format: "tap", File: "results.tap"},
+		{Format: "test2json", File: "results.json"},
+	}
+
+	_, err = commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.Error(t, err)
+
+	require.FileExists(t, path.Join(tmpDir, JunitReport))
+
+	tap, err := ioutil.ReadFile(filepath.Clean(path.Join(tmpDir, "results.tap")))
+	require.NoError(t, err)
+	require.Contains(t, string(tap), "TAP version 13\n1..2\n")
+	require.Regexp(t, `(?m)^ok \d - simple/a_provider/TestPass$`, string(tap))
+	require.Regexp(t, `(?m)^not ok \d - simple/a_provider/TestFail$`, string(tap))
+
+	events, err := ioutil.ReadFile(filepath.Clean(path.Join(tmpDir, "results.json")))
+	require.NoError(t, err)
+	require.Contains(t, string(events), `{"Action":"pass","Package":"simple/a_provider","Test":"TestPass"`)
+	require.Contains(t, string(events), `{"Action":"fail","Package":"simple/a_provider","Test":"TestFail"`)
+}
+
+func TestReportersUnknownFormat(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	createProvider(testConfig, "a_provider")
+	testConfig.Reporting.Reporters = []*config.ReporterConfig{{Format: "xunit", File: "results.xml"}}
+
+	_, err = commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown report format xunit")
+}
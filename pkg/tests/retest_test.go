@@ -20,6 +20,7 @@ package tests
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -190,8 +191,10 @@ func TestRestartRequestRestartCluster(t *testing.T) {
 		"Reached a limit of re-tests per cluster instance",
 		"Destroying cluster",
 		"Starting cluster ",
-		"Test TestRequestRestart retry count 3 exceed: err: failed to run go test",
+		"Test TestRequestRestart retry count 3 exceed: err: failed to run",
 	})
+	// Test is executed by binary precompiled once for all re-runs.
+	require.Equal(t, 1, logKeeper.MessageCount("retry count 3 exceed: err: failed to run \""+filepath.Join(tmpDir, "binaries", "1-sample.test")))
 	require.Equal(t, 3, logKeeper.MessageCount("Re schedule task TestRequestRestart reason: rerun-request"))
 }
 
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/commands"
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestRetryOnFailure(t *testing.T) {
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	createProvider(testConfig, "a_provider")
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:           "simple",
+		Timeout:        15,
+		PackageRoot:    "./sample",
+		RetryOnFailure: 1,
+		Source: config.ExecutionSource{
+			Tests: []string{"TestFail"},
+		},
+	})
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "there is failed tests 1")
+
+	require.Equal(t, 1, logKeeper.MessageCount("will be retried"))
+	require.Equal(t, 1, logKeeper.MessageCount("Re schedule task TestFail reason: retry-request"))
+
+	// Retry is executed on other instance, both attempts are reported.
+	testCases := report.Suites[0].Suites[0].Suites[0].TestCases
+	require.Len(t, testCases, 1)
+	require.NotNil(t, testCases[0].Failure)
+	require.Contains(t, testCases[0].Failure.Contents, "/a_provider-1/")
+	require.Contains(t, testCases[0].Failure.Contents, "/a_provider-2/")
+	require.Contains(t, testCases[0].Failure.Contents, "Execution attempt: 0")
+	require.Contains(t, testCases[0].Failure.Contents, "Execution attempt: 1")
+	require.False(t, testCases[0].Flaky)
+}
+
+func TestRetryOnFailureFlaky(t *testing.T) {
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	createProvider(testConfig, "a_provider")
+
+	// Only first attempt is failed, script is kept outside of config root cleared on start.
+	scriptDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-script")
+	require.NoError(t, err)
+	defer utils.ClearFolder(scriptDir, false)
+	script := filepath.Join(scriptDir, "flaky.sh")
+	require.NoError(t, ioutil.WriteFile(script, []byte(fmt.Sprintf(
+		"test -f %[1]s && exit 0\ntouch %[1]s\necho first attempt is failed\nexit 1\n", filepath.Join(scriptDir, "marker"))), 0600))
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:           "flaky",
+		Timeout:        15,
+		Kind:           "shell",
+		RetryOnFailure: 1,
+		Run:            "sh " + script,
+	})
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.NoError(t, err)
+
+	// Test is passed, failed attempt is reported with its output.
+	testCases := report.Suites[0].Suites[0].Suites[0].TestCases
+	require.Len(t, testCases, 1)
+	require.Nil(t, testCases[0].Failure)
+	require.True(t, testCases[0].Flaky)
+	require.Len(t, testCases[0].FlakyFailures, 1)
+	require.Contains(t, testCases[0].FlakyFailures[0].Message, "Execution attempt 0 is retry-request on a_provider-")
+	require.Contains(t, testCases[0].FlakyFailures[0].Contents, "first attempt is failed")
+	require.Equal(t, 0, report.Suites[0].Failures)
+}
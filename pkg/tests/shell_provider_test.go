@@ -19,8 +19,11 @@ package tests
 import (
 	"io/ioutil"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -28,6 +31,9 @@ import (
 
 	"github.com/networkservicemesh/cloudtest/pkg/commands"
 	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/execmanager"
+	"github.com/networkservicemesh/cloudtest/pkg/providers"
+	"github.com/networkservicemesh/cloudtest/pkg/providers/shell"
 	"github.com/networkservicemesh/cloudtest/pkg/utils"
 )
 
@@ -434,3 +440,82 @@ func TestGlobalTimeout(t *testing.T) {
 	require.Equal(t, 3, rootSuite.Suites[0].Tests)
 	require.Len(t, rootSuite.Suites[0].Suites[0].TestCases, 3)
 }
+
+func TestShellProviderDestroy(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	testConfig := config.NewCloudTestConfig()
+	providerConfig := createProvider(testConfig, "a_provider")
+	providerConfig.Scripts["stop"] = "echo stopped $(cluster-name)"
+
+	manager := execmanager.NewExecutionManager(tmpDir)
+	provider := shell.NewShellClusterProvider(path.Join(tmpDir, "shell"))
+
+	instance, err := provider.CreateCluster(providerConfig, &TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.NoError(t, err)
+
+	_, err = instance.Start(time.Minute)
+	require.NoError(t, err)
+	require.True(t, instance.IsRunning())
+
+	require.NoError(t, instance.Destroy(time.Minute))
+	require.False(t, instance.IsRunning())
+
+	stopLogs, err := utils.FilterByPattern(utils.GetAllFiles(path.Join(tmpDir, instance.GetID())), ".*-stop.log")
+	require.NoError(t, err)
+	require.Len(t, stopLogs, 1)
+
+	content, err := ioutil.ReadFile(filepath.Clean(stopLogs[0]))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "stopped a_provider-1")
+}
+
+func TestShellProviderDestroyFailed(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	testConfig := config.NewCloudTestConfig()
+	providerConfig := createProvider(testConfig, "a_provider")
+	providerConfig.Scripts["stop"] = "exit 2"
+
+	manager := execmanager.NewExecutionManager(tmpDir)
+	provider := shell.NewShellClusterProvider(path.Join(tmpDir, "shell"))
+
+	instance, err := provider.CreateCluster(providerConfig, &TestValidationFactory{}, manager, providers.InstanceOptions{})
+	require.NoError(t, err)
+
+	_, err = instance.Start(time.Minute)
+	require.NoError(t, err)
+
+	require.Error(t, instance.Destroy(time.Minute))
+	require.False(t, instance.IsRunning())
+}
+
+func TestShellProviderNoStop(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+
+	testConfig := config.NewCloudTestConfig()
+	providerConfig := createProvider(testConfig, "a_provider")
+
+	manager := execmanager.NewExecutionManager(tmpDir)
+	provider := shell.NewShellClusterProvider(path.Join(tmpDir, "shell"))
+
+	instance, err := provider.CreateCluster(providerConfig, &TestValidationFactory{}, manager, providers.InstanceOptions{
+		NoStop: true,
+	})
+	require.NoError(t, err)
+
+	_, err = instance.Start(time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, instance.Destroy(time.Minute))
+
+	stopLogs, err := utils.FilterByPattern(utils.GetAllFiles(path.Join(tmpDir, instance.GetID())), ".*-stop.log")
+	require.NoError(t, err)
+	require.Empty(t, stopLogs)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/cloudtest/pkg/commands"
+	"github.com/networkservicemesh/cloudtest/pkg/config"
+	"github.com/networkservicemesh/cloudtest/pkg/utils"
+)
+
+func TestWarmSpareReplacesFailedInstance(t *testing.T) {
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+	p.WarmSpares = 1
+	p.RetryCount = 0
+	// Only first instance is failed to start.
+	p.Scripts["start"] = "test $(cluster-name) != a_provider-1"
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "simple",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tags: []string{"passed"},
+		},
+		OnlyRun: []string{"TestPass1", "TestPass2"},
+	})
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.NoError(t, err)
+	require.NotNil(t, report)
+
+	// Cluster failures are not reported, since spare keeps provider available.
+	rootSuite := report.Suites[0]
+	require.Len(t, rootSuite.Suites, 1)
+	require.Equal(t, 2, rootSuite.Suites[0].Tests)
+	require.Equal(t, 0, rootSuite.Suites[0].Failures)
+
+	require.Equal(t, 1, logKeeper.MessageCount("Spare cluster instance a_provider-2 replaces a_provider-1"))
+}
+
+func TestWarmSparesShutdownWhenNoPendingTasks(t *testing.T) {
+	logKeeper := utils.NewLogKeeper()
+	defer logKeeper.Stop()
+
+	testConfig := config.NewCloudTestConfig()
+	testConfig.Timeout = 300
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "cloud-test-temp")
+	require.NoError(t, err)
+	defer utils.ClearFolder(tmpDir, false)
+	testConfig.ConfigRoot = tmpDir
+
+	p := createProvider(testConfig, "a_provider")
+	p.Instances = 1
+	p.WarmSpares = 2
+
+	testConfig.Executions = append(testConfig.Executions, &config.Execution{
+		Name:        "simple",
+		Timeout:     15,
+		PackageRoot: "./sample",
+		Source: config.ExecutionSource{
+			Tags: []string{"passed"},
+		},
+		OnlyRun: []string{"TestPass1", "TestPass2", "TestPass3"},
+	})
+
+	testConfig.Reporting.JUnitReportFile = JunitReport
+
+	report, err := commands.PerformTesting(testConfig, &TestValidationFactory{}, &commands.Arguments{})
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Equal(t, 3, report.Suites[0].Tests)
+	require.Equal(t, 0, logKeeper.MessageCount("replaces"))
+
+	// Both spares are shut down once last task is assigned, they are never used to run tasks.
+	require.Equal(t, 1, logKeeper.MessageCount("Shutdown of spare instance a_provider-2"))
+	require.Equal(t, 1, logKeeper.MessageCount("Shutdown of spare instance a_provider-3"))
+	require.Equal(t, 0, logKeeper.MessageCount("Restart of spare instance"))
+}
@@ -18,6 +18,7 @@ package utils
 
 import (
 	"bufio"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -60,6 +61,39 @@ func OpenFile(root, fileName string) (string, *os.File, error) {
 	return fileName, f, err
 }
 
+// ReplaceFile - atomically replace a file in folder with content, make folder parents if required.
+// Content is written to a temporary file in the same folder, which is renamed over the target.
+func ReplaceFile(root, fileName string, content []byte) (string, error) {
+	joinedRoot := path.Join(root, fileName)
+	root, fileName = path.Split(joinedRoot)
+	if !FileExists(root) {
+		_ = os.MkdirAll(root, os.ModePerm)
+	}
+	fileName = path.Join(root, fileName)
+
+	f, err := ioutil.TempFile(root, "."+path.Base(fileName)+".*")
+	if err != nil {
+		return fileName, err
+	}
+	tmpName := f.Name()
+	if _, err = f.Write(content); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmpName, fileName)
+	}
+	if err != nil {
+		_ = os.Remove(tmpName)
+	}
+	return fileName, err
+}
+
 // ReadFile - read a file contents and return as array of strings
 func ReadFile(fileName string) ([]string, error) {
 	// Create folder if it doesn't exists